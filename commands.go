@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/kevholditch/terraform-provider-kong/export"
+	"github.com/kevholditch/terraform-provider-kong/kong"
+)
+
+// commands are the subcommands of the provider binary, without one the binary serves the provider to terraform.
+var commands = map[string]func(args []string) error{
+	"export":  runExport,
+	"convert": runConvert,
+}
+
+// runExport writes the entities of an existing kong cluster as provider resources together with import blocks.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	adminURL := flags.String("admin-url", envOrDefault("KONG_ADMIN_ADDR", "http://localhost:8001"), "The address of the kong admin api")
+	username := flags.String("username", os.Getenv("KONG_ADMIN_USERNAME"), "Basic auth user for the kong admin api")
+	password := flags.String("password", os.Getenv("KONG_ADMIN_PASSWORD"), "Basic auth password for the kong admin api")
+	apiKey := flags.String("api-key", os.Getenv("KONG_API_KEY"), "API key for the kong admin api")
+	adminToken := flags.String("admin-token", os.Getenv("KONG_ADMIN_TOKEN"), "Admin token for the kong admin api (Enterprise Edition)")
+	workspace := flags.String("workspace", "", "Workspace to export (Enterprise Edition)")
+	tlsSkipVerify := flags.Bool("tls-skip-verify", os.Getenv("TLS_SKIP_VERIFY") == "true", "Skip tls verification of the kong admin api")
+	output := flags.String("output", "", "File to write the configuration to, standard output when not set")
+	includeSecrets := flags.Bool("include-secrets", false, "Write passwords, keys and secrets into the configuration instead of referring to variables")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := kong.GetKongClient(kong.Config{
+		Address:            *adminURL,
+		Username:           *username,
+		Password:           *password,
+		InsecureSkipVerify: *tlsSkipVerify,
+		APIKey:             *apiKey,
+		AdminToken:         *adminToken,
+		Workspace:          *workspace,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		return export.Cluster(context.Background(), client, w, *includeSecrets)
+	})
+}
+
+// runConvert writes the entities of a decK state file as provider resources.
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	output := flags.String("output", "", "File to write the configuration to, standard output when not set")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: terraform-provider-kong convert [--output kong.tf] kong.yaml")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("convert expects the decK file to convert, - for standard input")
+	}
+
+	var data []byte
+	var err error
+	if input := flags.Arg(0); input == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(input)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		skipped, err := export.Deck(data, w)
+		for _, section := range skipped {
+			fmt.Fprintf(os.Stderr, "skipped %s, the section is not converted\n", section)
+		}
+		return err
+	})
+}
+
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %v", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func envOrDefault(key string, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
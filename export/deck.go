@@ -0,0 +1,330 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+	"gopkg.in/yaml.v2"
+)
+
+// deckSections are the top level sections of a decK file that are converted.
+var deckSections = []string{"certificates", "services", "routes", "upstreams", "consumers", "plugins"}
+
+// deckNestedSections are the sections nested in a decK entity that hold entities of their own.
+var deckNestedSections = []string{
+	"routes", "plugins", "targets",
+	"keyauth_credentials", "basicauth_credentials", "acls", "jwt_secrets", "oauth2_credentials",
+}
+
+// deckCredentials maps the credential sections of a decK consumer onto the exporter method adding them.
+var deckCredentials = []struct {
+	section string
+	add     func(e *Exporter, credential map[string]interface{}) error
+}{
+	{"keyauth_credentials", func(e *Exporter, credential map[string]interface{}) error {
+		keyAuth := &kong.KeyAuth{}
+		return addDeckEntity(credential, keyAuth, func() { e.AddKeyAuth(keyAuth) })
+	}},
+	{"basicauth_credentials", func(e *Exporter, credential map[string]interface{}) error {
+		basicAuth := &kong.BasicAuth{}
+		return addDeckEntity(credential, basicAuth, func() { e.AddBasicAuth(basicAuth) })
+	}},
+	{"acls", func(e *Exporter, credential map[string]interface{}) error {
+		aclGroup := &kong.ACLGroup{}
+		return addDeckEntity(credential, aclGroup, func() { e.AddACLGroup(aclGroup) })
+	}},
+	{"jwt_secrets", func(e *Exporter, credential map[string]interface{}) error {
+		jwtAuth := &kong.JWTAuth{}
+		return addDeckEntity(credential, jwtAuth, func() { e.AddJWTAuth(jwtAuth) })
+	}},
+	{"oauth2_credentials", func(e *Exporter, credential map[string]interface{}) error {
+		oauth2Credential := &kong.Oauth2Credential{}
+		return addDeckEntity(credential, oauth2Credential, func() { e.AddOAuth2(oauth2Credential) })
+	}},
+}
+
+// Deck converts a decK state file into provider resources. Entities nested in a decK file, such as the routes of a
+// service, and entities referring to one another by name or id become terraform references. The names of the top
+// level sections that are not converted, such as ca_certificates, are returned so they can be reported.
+func Deck(data []byte, w io.Writer) ([]string, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse decK file: %v", err)
+	}
+	file, ok := jsonCompatible(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not parse decK file: expected a map at the top level")
+	}
+
+	var skipped []string
+	for section := range file {
+		if !strings.HasPrefix(section, "_") && !contains(deckSections, section) {
+			skipped = append(skipped, section)
+		}
+	}
+	sort.Strings(skipped)
+
+	// a decK file holds its secrets in plaintext already, they are converted as they are.
+	e := NewExporter(false, true)
+	// plugins are added last as they can refer to every other kind of entity.
+	var plugins []map[string]interface{}
+
+	for _, certificate := range deckList(file, "certificates") {
+		var snis []interface{}
+		for _, sni := range deckList(certificate, "snis") {
+			snis = append(snis, sni["name"])
+		}
+		for _, sni := range deckStrings(certificate["snis"]) {
+			snis = append(snis, sni)
+		}
+		certificate["snis"] = snis
+
+		entity := &kong.Certificate{}
+		if err := addDeckEntity(certificate, entity, func() { e.AddCertificate(entity) }); err != nil {
+			return nil, err
+		}
+	}
+
+	var routes []map[string]interface{}
+	for _, service := range deckList(file, "services") {
+		if err := expandDeckServiceURL(service); err != nil {
+			return nil, err
+		}
+		if service["protocol"] == nil {
+			service["protocol"] = "http"
+		}
+		service["client_certificate"] = deckReference(service["client_certificate"])
+
+		entity := &kong.Service{}
+		if err := addDeckEntity(service, entity, func() { e.AddService(entity) }); err != nil {
+			return nil, err
+		}
+		parent := deckForeignKey(entity.ID, entity.Name)
+
+		for _, route := range deckList(service, "routes") {
+			route["service"] = parent
+			routes = append(routes, route)
+		}
+		for _, plugin := range deckList(service, "plugins") {
+			plugin["service"] = parent
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	for _, route := range append(routes, deckList(file, "routes")...) {
+		route["service"] = deckReference(route["service"])
+		if route["protocols"] == nil {
+			route["protocols"] = []interface{}{"http", "https"}
+		}
+
+		entity := &kong.Route{}
+		if err := addDeckEntity(route, entity, func() { e.AddRoute(entity) }); err != nil {
+			return nil, err
+		}
+		for _, plugin := range deckList(route, "plugins") {
+			plugin["route"] = deckForeignKey(entity.ID, entity.Name)
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	for _, upstream := range deckList(file, "upstreams") {
+		upstream["client_certificate"] = deckReference(upstream["client_certificate"])
+
+		entity := &kong.Upstream{}
+		if err := addDeckEntity(upstream, entity, func() { e.AddUpstream(entity) }); err != nil {
+			return nil, err
+		}
+		for _, target := range deckList(upstream, "targets") {
+			target["upstream"] = deckForeignKey(entity.ID, entity.Name)
+
+			targetEntity := &kong.Target{}
+			if err := addDeckEntity(target, targetEntity, func() { e.AddTarget(targetEntity) }); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, consumer := range deckList(file, "consumers") {
+		entity := &kong.Consumer{}
+		if err := addDeckEntity(consumer, entity, func() { e.AddConsumer(entity) }); err != nil {
+			return nil, err
+		}
+		parent := deckForeignKey(entity.ID, entity.Username, entity.CustomID)
+
+		for _, credentials := range deckCredentials {
+			for _, credential := range deckList(consumer, credentials.section) {
+				credential["consumer"] = parent
+				if err := credentials.add(e, credential); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, plugin := range deckList(consumer, "plugins") {
+			plugin["consumer"] = parent
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	for _, plugin := range append(plugins, deckList(file, "plugins")...) {
+		for _, scope := range []string{"service", "route", "consumer"} {
+			plugin[scope] = deckReference(plugin[scope])
+		}
+
+		entity := &kong.Plugin{}
+		if err := addDeckEntity(plugin, entity, func() { e.AddPlugin(entity) }); err != nil {
+			return nil, err
+		}
+	}
+
+	return skipped, e.Write(w)
+}
+
+// addDeckEntity decodes an entity of a decK file into its go-kong type and adds it. Nested sections are removed first,
+// they are converted on their own.
+func addDeckEntity(entity map[string]interface{}, out interface{}, add func()) error {
+	fields := map[string]interface{}{}
+	for key, value := range entity {
+		if _, nested := value.([]interface{}); nested && contains(deckNestedSections, key) {
+			continue
+		}
+		fields[key] = value
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("could not read decK entity %v: %v", describeDeckEntity(entity), err)
+	}
+	add()
+	return nil
+}
+
+func describeDeckEntity(entity map[string]interface{}) string {
+	for _, key := range []string{"name", "username", "id", "target", "group"} {
+		if value, ok := entity[key].(string); ok {
+			return value
+		}
+	}
+	return "without a name"
+}
+
+// deckReference turns a reference decK writes as a name or id into the shape of a go-kong foreign key. The value is
+// looked up by the exporter as an id or a name so either resolves to the resource it refers to.
+func deckReference(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"id": v}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		if v["id"] == nil {
+			for _, key := range []string{"name", "username"} {
+				if name, ok := v[key]; ok && name != nil {
+					v["id"] = name
+				}
+			}
+		}
+		return v
+	}
+	return nil
+}
+
+// deckForeignKey refers to the entity a nested decK entity belongs to by its id, or its name when it has no id.
+func deckForeignKey(keys ...*string) map[string]interface{} {
+	for _, key := range keys {
+		if key != nil && *key != "" {
+			return map[string]interface{}{"id": *key}
+		}
+	}
+	return nil
+}
+
+// expandDeckServiceURL splits the url decK allows on a service into the protocol, host, port and path fields.
+func expandDeckServiceURL(service map[string]interface{}) error {
+	raw, ok := service["url"].(string)
+	if !ok {
+		return nil
+	}
+	delete(service, "url")
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("could not parse url of service %v: %v", describeDeckEntity(service), err)
+	}
+
+	service["protocol"] = u.Scheme
+	service["host"] = u.Hostname()
+	if port := u.Port(); port != "" {
+		if service["port"], err = strconv.Atoi(port); err != nil {
+			return fmt.Errorf("could not parse url of service %v: %v", describeDeckEntity(service), err)
+		}
+	} else if u.Scheme == "https" {
+		service["port"] = 443
+	} else {
+		service["port"] = 80
+	}
+	if u.Path != "" {
+		service["path"] = u.Path
+	}
+	return nil
+}
+
+// deckList returns the entities of a section, entries that are not maps such as the plain SNI names of a certificate
+// are skipped.
+func deckList(parent map[string]interface{}, section string) []map[string]interface{} {
+	items, _ := parent[section].([]interface{})
+	var entities []map[string]interface{}
+	for _, item := range items {
+		if entity, ok := item.(map[string]interface{}); ok {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+func deckStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var strs []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// jsonCompatible converts the maps yaml decodes, keyed by interface{}, into maps keyed by string.
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	}
+	return value
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,576 @@
+// Package export writes kong entities as terraform configuration for this provider.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// Exporter turns kong entities into provider resources. Entities have to be added before the entities that refer to
+// them, a service before its routes, so that the reference is written as a terraform reference rather than an id.
+type Exporter struct {
+	imports        bool
+	includeSecrets bool
+	blocks         []*Block
+	variables      []*Block
+	names          map[string]bool
+	// refs maps the kind and the id or name of an entity onto the address of its resource.
+	refs map[string]string
+}
+
+// NewExporter returns an exporter, with imports set every resource is followed by an import block for its kong id.
+// Without includeSecrets passwords, keys and secrets are written as references to sensitive variables rather than as
+// their values.
+func NewExporter(imports bool, includeSecrets bool) *Exporter {
+	return &Exporter{
+		imports:        imports,
+		includeSecrets: includeSecrets,
+		names:          map[string]bool{},
+		refs:           map[string]string{},
+	}
+}
+
+// Write writes the variables for the secrets left out and the resources added so far.
+func (e *Exporter) Write(w io.Writer) error {
+	return Write(w, append(append([]*Block{}, e.variables...), e.blocks...))
+}
+
+// Cluster walks the entities of a kong cluster and writes them to w as resources with matching import blocks, the
+// values of secrets are only written with includeSecrets.
+func Cluster(ctx context.Context, client *kong.Client, w io.Writer, includeSecrets bool) error {
+	e := NewExporter(true, includeSecrets)
+
+	certificates, err := client.Certificates.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong certificates: %v", err)
+	}
+	for _, certificate := range certificates {
+		e.AddCertificate(certificate)
+	}
+
+	services, err := client.Services.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong services: %v", err)
+	}
+	for _, service := range services {
+		e.AddService(service)
+	}
+
+	routes, err := client.Routes.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong routes: %v", err)
+	}
+	for _, route := range routes {
+		e.AddRoute(route)
+	}
+
+	upstreams, err := client.Upstreams.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong upstreams: %v", err)
+	}
+	for _, upstream := range upstreams {
+		e.AddUpstream(upstream)
+
+		targets, err := client.Targets.ListAll(ctx, upstream.ID)
+		if err != nil {
+			return fmt.Errorf("could not list kong targets for upstream: %s error: %v", *upstream.ID, err)
+		}
+		for _, target := range targets {
+			e.AddTarget(target)
+		}
+	}
+
+	consumers, err := client.Consumers.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong consumers: %v", err)
+	}
+	for _, consumer := range consumers {
+		e.AddConsumer(consumer)
+	}
+
+	keyAuths, err := client.KeyAuths.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong key auth credentials: %v", err)
+	}
+	for _, keyAuth := range keyAuths {
+		e.AddKeyAuth(keyAuth)
+	}
+
+	basicAuths, err := client.BasicAuths.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong basic auth credentials: %v", err)
+	}
+	for _, basicAuth := range basicAuths {
+		e.AddBasicAuth(basicAuth)
+	}
+
+	aclGroups, err := client.ACLs.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong ACL groups: %v", err)
+	}
+	for _, aclGroup := range aclGroups {
+		e.AddACLGroup(aclGroup)
+	}
+
+	jwtAuths, err := client.JWTAuths.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong jwt credentials: %v", err)
+	}
+	for _, jwtAuth := range jwtAuths {
+		e.AddJWTAuth(jwtAuth)
+	}
+
+	oauth2Credentials, err := client.Oauth2Credentials.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong oauth2 credentials: %v", err)
+	}
+	for _, oauth2Credential := range oauth2Credentials {
+		e.AddOAuth2(oauth2Credential)
+	}
+
+	plugins, err := client.Plugins.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list kong plugins: %v", err)
+	}
+	for _, plugin := range plugins {
+		e.AddPlugin(plugin)
+	}
+
+	return e.Write(w)
+}
+
+// AddCertificate adds a kong_certificate.
+func (e *Exporter) AddCertificate(certificate *kong.Certificate) {
+	name := "certificate"
+	if len(certificate.SNIs) > 0 && certificate.SNIs[0] != nil {
+		name = *certificate.SNIs[0]
+	}
+
+	block := e.resource("kong_certificate", name, "certificate", certificate.ID, nil, certificate.ID)
+	for _, sni := range certificate.SNIs {
+		if sni != nil {
+			e.refs["certificate:"+*sni] = "kong_certificate." + block.Labels[1]
+		}
+	}
+	block.Set("certificate", certificate.Cert)
+	e.secret(block, "private_key", certificate.Key)
+	block.Set("certificate_alt", certificate.CertAlt)
+	e.secret(block, "private_key_alt", certificate.KeyAlt)
+	block.Set("snis", certificate.SNIs)
+	block.Set("tags", certificate.Tags)
+}
+
+// AddService adds a kong_service.
+func (e *Exporter) AddService(service *kong.Service) {
+	block := e.resource("kong_service", stringValue(service.Name), "service", service.ID, service.Name, service.ID)
+	block.Set("name", service.Name)
+	block.Set("protocol", service.Protocol)
+	block.Set("host", service.Host)
+	block.Set("port", service.Port)
+	block.Set("path", service.Path)
+	block.Set("retries", service.Retries)
+	block.Set("connect_timeout", service.ConnectTimeout)
+	block.Set("write_timeout", service.WriteTimeout)
+	block.Set("read_timeout", service.ReadTimeout)
+	block.Set("tls_verify", service.TLSVerify)
+	block.Set("tls_verify_depth", service.TLSVerifyDepth)
+	if service.ClientCertificate != nil {
+		block.Set("client_certificate_id", e.ref("certificate", service.ClientCertificate.ID))
+	}
+	block.Set("ca_certificate_ids", service.CACertificates)
+	block.Set("tags", service.Tags)
+}
+
+// AddRoute adds a kong_route.
+func (e *Exporter) AddRoute(route *kong.Route) {
+	name := stringValue(route.Name)
+	if name == "" && route.Service != nil {
+		name = e.label("service", route.Service.ID, route.Service.Name) + "_route"
+	}
+
+	block := e.resource("kong_route", name, "route", route.ID, route.Name, route.ID)
+	block.Set("name", route.Name)
+	block.Set("protocols", route.Protocols)
+	block.Set("methods", route.Methods)
+	block.Set("hosts", route.Hosts)
+	block.Set("paths", route.Paths)
+	block.Set("path_handling", route.PathHandling)
+	block.Set("strip_path", route.StripPath)
+	block.Set("preserve_host", route.PreserveHost)
+	block.Set("regex_priority", route.RegexPriority)
+	block.Set("https_redirect_status_code", route.HTTPSRedirectStatusCode)
+	block.Set("request_buffering", route.RequestBuffering)
+	block.Set("response_buffering", route.ResponseBuffering)
+	block.Set("snis", route.SNIs)
+	if route.Service != nil {
+		block.Set("service_id", e.ref("service", route.Service.ID, route.Service.Name))
+	}
+	block.Set("tags", route.Tags)
+
+	for _, source := range route.Sources {
+		block.AddBlock("source").Set("ip", source.IP).Set("port", source.Port)
+	}
+	for _, destination := range route.Destinations {
+		block.AddBlock("destination").Set("ip", destination.IP).Set("port", destination.Port)
+	}
+
+	var headers []string
+	for header := range route.Headers {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+	for _, header := range headers {
+		block.AddBlock("header").Set("name", header).Set("values", route.Headers[header])
+	}
+}
+
+// AddUpstream adds a kong_upstream.
+func (e *Exporter) AddUpstream(upstream *kong.Upstream) {
+	block := e.resource("kong_upstream", stringValue(upstream.Name), "upstream", upstream.ID, upstream.Name, upstream.ID)
+	block.Set("name", upstream.Name)
+	block.Set("algorithm", upstream.Algorithm)
+	block.Set("slots", upstream.Slots)
+	block.Set("hash_on", upstream.HashOn)
+	block.Set("hash_fallback", upstream.HashFallback)
+	block.Set("hash_on_header", upstream.HashOnHeader)
+	block.Set("hash_fallback_header", upstream.HashFallbackHeader)
+	block.Set("hash_on_cookie", upstream.HashOnCookie)
+	block.Set("hash_on_cookie_path", upstream.HashOnCookiePath)
+	block.Set("host_header", upstream.HostHeader)
+	if upstream.ClientCertificate != nil {
+		block.Set("client_certificate_id", e.ref("certificate", upstream.ClientCertificate.ID))
+	}
+	block.Set("tags", upstream.Tags)
+
+	if healthchecks := upstream.Healthchecks; healthchecks != nil {
+		healthchecksBlock := block.AddBlock("healthchecks").Set("threshold", healthchecks.Threshold)
+
+		if active := healthchecks.Active; active != nil {
+			activeBlock := healthchecksBlock.AddBlock("active")
+			activeBlock.Set("type", active.Type)
+			activeBlock.Set("timeout", active.Timeout)
+			activeBlock.Set("concurrency", active.Concurrency)
+			activeBlock.Set("http_path", active.HTTPPath)
+			activeBlock.Set("https_verify_certificate", active.HTTPSVerifyCertificate)
+			activeBlock.Set("https_sni", active.HTTPSSni)
+			if healthy := active.Healthy; healthy != nil {
+				activeBlock.AddBlock("healthy").
+					Set("interval", healthy.Interval).
+					Set("http_statuses", healthy.HTTPStatuses).
+					Set("successes", healthy.Successes)
+			}
+			if unhealthy := active.Unhealthy; unhealthy != nil {
+				activeBlock.AddBlock("unhealthy").
+					Set("interval", unhealthy.Interval).
+					Set("http_statuses", unhealthy.HTTPStatuses).
+					Set("tcp_failures", unhealthy.TCPFailures).
+					Set("http_failures", unhealthy.HTTPFailures).
+					Set("timeouts", unhealthy.Timeouts)
+			}
+		}
+
+		if passive := healthchecks.Passive; passive != nil {
+			passiveBlock := healthchecksBlock.AddBlock("passive").Set("type", passive.Type)
+			if healthy := passive.Healthy; healthy != nil {
+				passiveBlock.AddBlock("healthy").
+					Set("http_statuses", healthy.HTTPStatuses).
+					Set("successes", healthy.Successes)
+			}
+			if unhealthy := passive.Unhealthy; unhealthy != nil {
+				passiveBlock.AddBlock("unhealthy").
+					Set("http_statuses", unhealthy.HTTPStatuses).
+					Set("tcp_failures", unhealthy.TCPFailures).
+					Set("http_failures", unhealthy.HTTPFailures).
+					Set("timeouts", unhealthy.Timeouts)
+			}
+		}
+	}
+}
+
+// AddTarget adds a kong_target.
+func (e *Exporter) AddTarget(target *kong.Target) {
+	var upstreamID, upstreamName *string
+	if target.Upstream != nil {
+		upstreamID, upstreamName = target.Upstream.ID, target.Upstream.Name
+	}
+	name := e.label("upstream", upstreamID, upstreamName) + "_" + stringValue(target.Target)
+
+	var importID *string
+	if upstreamID != nil && target.ID != nil {
+		importID = kong.String(*upstreamID + "/" + *target.ID)
+	}
+
+	block := e.resource("kong_target", name, "target", target.ID, nil, importID)
+	block.Set("target", target.Target)
+	block.Set("weight", target.Weight)
+	block.Set("upstream_id", e.ref("upstream", upstreamID, upstreamName))
+	block.Set("tags", target.Tags)
+}
+
+// AddConsumer adds a kong_consumer.
+func (e *Exporter) AddConsumer(consumer *kong.Consumer) {
+	name := stringValue(consumer.Username)
+	if name == "" {
+		name = stringValue(consumer.CustomID)
+	}
+
+	block := e.resource("kong_consumer", name, "consumer", consumer.ID, consumer.Username, consumer.ID)
+	if consumer.CustomID != nil {
+		e.refs["consumer:"+*consumer.CustomID] = e.refs["consumer:"+stringValue(consumer.ID)]
+	}
+	block.Set("username", consumer.Username)
+	block.Set("custom_id", consumer.CustomID)
+	block.Set("tags", consumer.Tags)
+}
+
+// AddKeyAuth adds a kong_consumer_key_auth.
+func (e *Exporter) AddKeyAuth(keyAuth *kong.KeyAuth) {
+	block := e.credential("kong_consumer_key_auth", "key_auth", keyAuth.ID, keyAuth.Consumer)
+	e.secret(block, "key", keyAuth.Key)
+	block.Set("ttl", keyAuth.TTL)
+	block.Set("tags", keyAuth.Tags)
+}
+
+// AddBasicAuth adds a kong_consumer_basic_auth. Kong only returns the hash of the password, so a hash is written with
+// a comment asking for the password to be filled in.
+func (e *Exporter) AddBasicAuth(basicAuth *kong.BasicAuth) {
+	block := e.credential("kong_consumer_basic_auth", "basic_auth", basicAuth.ID, basicAuth.Consumer)
+	block.Set("username", basicAuth.Username)
+	e.secret(block, "password", basicAuth.Password)
+	block.Set("tags", basicAuth.Tags)
+	if e.includeSecrets && basicAuth.Password != nil && passwordHash.MatchString(*basicAuth.Password) {
+		block.Comment = "kong only returns the hash of the password, replace it with the password before the next apply"
+	}
+}
+
+// AddACLGroup adds a kong_consumer_acl.
+func (e *Exporter) AddACLGroup(aclGroup *kong.ACLGroup) {
+	block := e.credential("kong_consumer_acl", "acl_"+stringValue(aclGroup.Group), aclGroup.ID, aclGroup.Consumer)
+	block.Set("group", aclGroup.Group)
+	block.Set("tags", aclGroup.Tags)
+}
+
+// AddJWTAuth adds a kong_consumer_jwt_auth.
+func (e *Exporter) AddJWTAuth(jwtAuth *kong.JWTAuth) {
+	block := e.credential("kong_consumer_jwt_auth", "jwt_auth", jwtAuth.ID, jwtAuth.Consumer)
+	block.Set("algorithm", jwtAuth.Algorithm)
+	block.Set("key", jwtAuth.Key)
+	block.Set("rsa_public_key", stringValue(jwtAuth.RSAPublicKey))
+	e.secret(block, "secret", jwtAuth.Secret)
+	block.Set("tags", jwtAuth.Tags)
+}
+
+// AddOAuth2 adds a kong_consumer_oauth2.
+func (e *Exporter) AddOAuth2(oauth2Credential *kong.Oauth2Credential) {
+	block := e.credential("kong_consumer_oauth2", "oauth2", oauth2Credential.ID, oauth2Credential.Consumer)
+	block.Set("name", oauth2Credential.Name)
+	block.Set("client_id", oauth2Credential.ClientID)
+	e.secret(block, "client_secret", oauth2Credential.ClientSecret)
+	block.Set("hash_secret", oauth2Credential.HashSecret)
+	block.Set("redirect_uris", oauth2Credential.RedirectURIs)
+	block.Set("tags", oauth2Credential.Tags)
+}
+
+// AddPlugin adds a kong_plugin scoped to the service, route and consumer it applies to.
+func (e *Exporter) AddPlugin(plugin *kong.Plugin) {
+	var scope []string
+	if plugin.Service != nil {
+		scope = append(scope, e.label("service", plugin.Service.ID, plugin.Service.Name))
+	}
+	if plugin.Route != nil {
+		scope = append(scope, e.label("route", plugin.Route.ID, plugin.Route.Name))
+	}
+	if plugin.Consumer != nil {
+		scope = append(scope, e.label("consumer", plugin.Consumer.ID, plugin.Consumer.Username))
+	}
+	name := strings.Join(append(scope, stringValue(plugin.Name)), "_")
+
+	block := e.resource("kong_plugin", name, "plugin", plugin.ID, nil, plugin.ID)
+	block.Set("name", plugin.Name)
+	if plugin.Service != nil {
+		block.Set("service_id", e.ref("service", plugin.Service.ID, plugin.Service.Name))
+	}
+	if plugin.Route != nil {
+		block.Set("route_id", e.ref("route", plugin.Route.ID, plugin.Route.Name))
+	}
+	if plugin.Consumer != nil {
+		block.Set("consumer_id", e.ref("consumer", plugin.Consumer.ID, plugin.Consumer.Username))
+	}
+	block.Set("enabled", plugin.Enabled)
+	if config := pluginConfigJSON(plugin.Config); config != "" {
+		block.Set("config_json", config)
+	}
+	block.Set("tags", plugin.Tags)
+}
+
+// secret sets an attribute holding a secret. Without includeSecrets the attribute refers to a sensitive variable named
+// after the resource and the attribute, its value has to be passed in when applying.
+func (e *Exporter) secret(block *Block, name string, value *string) {
+	if value == nil {
+		return
+	}
+	if e.includeSecrets {
+		block.Set(name, value)
+		return
+	}
+
+	variable := e.uniqueName("variable", block.Labels[1]+"_"+name)
+	e.variables = append(e.variables, NewBlock("variable", variable).Set("type", Expression("string")).Set("sensitive", true))
+	block.Set(name, Expression("var."+variable))
+}
+
+// credential adds a resource for a credential of a consumer, its import id pairs the credential with the consumer.
+func (e *Exporter) credential(resourceType string, suffix string, id *string, consumer *kong.Consumer) *Block {
+	var consumerID, username *string
+	if consumer != nil {
+		consumerID, username = consumer.ID, consumer.Username
+	}
+
+	var importID *string
+	if id != nil && consumerID != nil {
+		importID = kong.String(*id + "|" + *consumerID)
+	}
+
+	block := e.resource(resourceType, e.label("consumer", consumerID, username)+"_"+suffix, "", id, nil, importID)
+	block.Set("consumer_id", e.ref("consumer", consumerID, username))
+	return block
+}
+
+// resource adds a resource block, registers it so later entities can refer to it by kind and id or name, and adds
+// its import block.
+func (e *Exporter) resource(resourceType string, name string, kind string, id *string, alias *string, importID *string) *Block {
+	name = e.uniqueName(resourceType, name)
+	address := resourceType + "." + name
+
+	if kind != "" {
+		for _, key := range []*string{id, alias} {
+			if key != nil && *key != "" {
+				e.refs[kind+":"+*key] = address
+			}
+		}
+	}
+
+	block := NewBlock("resource", resourceType, name)
+	e.blocks = append(e.blocks, block)
+
+	if e.imports && importID != nil {
+		e.blocks = append(e.blocks, NewBlock("import").Set("to", Expression(address)).Set("id", *importID))
+	}
+
+	return block
+}
+
+// ref returns a reference to the resource exported for an entity, or the id itself when the entity was not exported.
+func (e *Exporter) ref(kind string, keys ...*string) interface{} {
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		if address, ok := e.refs[kind+":"+*key]; ok {
+			return Expression(address + ".id")
+		}
+	}
+	for _, key := range keys {
+		if key != nil {
+			return *key
+		}
+	}
+	return nil
+}
+
+// label returns the resource name of an entity to build the names of the entities that belong to it.
+func (e *Exporter) label(kind string, keys ...*string) string {
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		if address, ok := e.refs[kind+":"+*key]; ok {
+			return address[strings.Index(address, ".")+1:]
+		}
+	}
+	for _, key := range keys {
+		if key != nil {
+			return resourceName(*key)
+		}
+	}
+	return kind
+}
+
+// passwordHash matches the salted SHA-1 hash kong stores basic auth passwords as.
+var passwordHash = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceName turns a kong name into a valid terraform resource name.
+func resourceName(name string) string {
+	name = strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "unnamed"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+func (e *Exporter) uniqueName(resourceType string, name string) string {
+	name = resourceName(name)
+	unique := name
+	for i := 2; e.names[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	e.names[resourceType+"."+unique] = true
+	return unique
+}
+
+// pluginConfigJSON renders a plugin config for config_json, fields kong left unset are dropped.
+func pluginConfigJSON(config kong.Configuration) string {
+	cleaned := dropNulls(map[string]interface{}(config))
+	if m, ok := cleaned.(map[string]interface{}); !ok || len(m) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cleaned); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+func dropNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		cleaned := map[string]interface{}{}
+		for key, item := range v {
+			if item = dropNulls(item); item != nil {
+				cleaned[key] = item
+			}
+		}
+		return cleaned
+	case []interface{}:
+		for i, item := range v {
+			v[i] = dropNulls(item)
+		}
+		return v
+	}
+	return value
+}
+
+// stringValue returns the string a pointer points to or an empty string for nil.
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	provider "github.com/kevholditch/terraform-provider-kong/kong"
+	"github.com/kong/go-kong/kong"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// assertGolden compares output with a file in testdata, running the tests with -update rewrites the file.
+func assertGolden(t *testing.T, name string, output []byte) {
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(golden, output, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, output) {
+		t.Errorf("output does not match %s, got:\n%s", golden, output)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	fixture, err := ioutil.ReadFile(filepath.Join("testdata", "cluster.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entities map[string][]interface{}
+	if err := json.Unmarshal(fixture, &entities); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := entities[r.URL.Path]
+		if !ok || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "next": nil})
+	}))
+	defer server.Close()
+
+	client, err := kong.NewClient(kong.String(server.URL), server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var withSecrets bytes.Buffer
+	if err := Cluster(context.Background(), client, &withSecrets, true); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "cluster_secrets.golden.tf", withSecrets.Bytes())
+
+	var output bytes.Buffer
+	if err := Cluster(context.Background(), client, &output, false); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "cluster.golden.tf", output.Bytes())
+
+	for _, secret := range []string{"PRIVATE KEY", "k3y", "8e9b2d8f0c4a1e7b6d5c3a2f1e0d9c8b7a6f5e4d", "jwt-s3cret", "oauth-s3cret"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("expected %s to be left out of the export without include secrets", secret)
+		}
+	}
+
+	// terraform rejects the whole file when one of its import blocks targets a resource without an importer.
+	resources := provider.Provider().ResourcesMap
+	for _, match := range importTarget.FindAllStringSubmatch(output.String(), -1) {
+		if resource, ok := resources[match[1]]; !ok || resource.Importer == nil {
+			t.Errorf("expected %s to be importable, the export writes import blocks for it", match[1])
+		}
+	}
+}
+
+var importTarget = regexp.MustCompile(`(?m)^\s+to = (kong_[a-z0-9_]+)\.`)
+
+func TestResourceName(t *testing.T) {
+	tests := map[string]string{
+		"orders":          "orders",
+		"Orders.Upstream": "orders_upstream",
+		"10.0.0.1:8080":   "_10_0_0_1_8080",
+		"mobile-app":      "mobile_app",
+		"***":             "unnamed",
+	}
+
+	for name, expected := range tests {
+		if actual := resourceName(name); actual != expected {
+			t.Errorf("expected resourceName(%q) to be %s got %s", name, expected, actual)
+		}
+	}
+}
+
+func TestDeck(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "deck", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("expected decK files in testdata/deck")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var output bytes.Buffer
+			skipped, err := Deck(data, &output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, section := range skipped {
+				output.WriteString("# skipped " + section + "\n")
+			}
+
+			assertGolden(t, filepath.Join("deck", name+".golden.tf"), output.Bytes())
+		})
+	}
+}
+
+func TestDeckInvalid(t *testing.T) {
+	if _, err := Deck([]byte("services: [\n"), &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a file that is not yaml")
+	}
+	if _, err := Deck([]byte("- services\n"), &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a file that is not a map")
+	}
+	if _, err := Deck([]byte("services:\n- name: broken\n  url: \"http://[::1\"\n"), &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a service with an invalid url")
+	}
+}
@@ -0,0 +1,239 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is written to HCL as is, for references such as kong_service.example.id.
+type Expression string
+
+// Block is an HCL block such as a resource, an import or a nested block like the header of a route.
+type Block struct {
+	Type    string
+	Labels  []string
+	Comment string
+
+	attributes []attribute
+	blocks     []*Block
+}
+
+type attribute struct {
+	name  string
+	value string
+	// multiline values such as heredocs are not aligned with the attributes around them.
+	multiline bool
+}
+
+// NewBlock returns an empty block of the given type.
+func NewBlock(blockType string, labels ...string) *Block {
+	return &Block{Type: blockType, Labels: labels}
+}
+
+// Set adds an attribute to the block. Nil pointers and empty lists are left out so that kong's defaults stay computed.
+func (b *Block) Set(name string, value interface{}) *Block {
+	var rendered string
+
+	switch v := value.(type) {
+	case nil:
+		return b
+	case Expression:
+		rendered = string(v)
+	case string:
+		rendered = hclString(v)
+	case *string:
+		if v == nil {
+			return b
+		}
+		rendered = hclString(*v)
+	case int:
+		rendered = strconv.Itoa(v)
+	case *int:
+		if v == nil {
+			return b
+		}
+		rendered = strconv.Itoa(*v)
+	case *float64:
+		if v == nil {
+			return b
+		}
+		rendered = strconv.FormatFloat(*v, 'f', -1, 64)
+	case bool:
+		rendered = strconv.FormatBool(v)
+	case *bool:
+		if v == nil {
+			return b
+		}
+		rendered = strconv.FormatBool(*v)
+	case []string:
+		if len(v) == 0 {
+			return b
+		}
+		var items []string
+		for _, item := range v {
+			items = append(items, hclQuote(item))
+		}
+		rendered = "[" + strings.Join(items, ", ") + "]"
+	case []int:
+		if len(v) == 0 {
+			return b
+		}
+		var items []string
+		for _, item := range v {
+			items = append(items, strconv.Itoa(item))
+		}
+		rendered = "[" + strings.Join(items, ", ") + "]"
+	case []*string:
+		var items []string
+		for _, item := range v {
+			if item != nil {
+				items = append(items, *item)
+			}
+		}
+		return b.Set(name, items)
+	case []Expression:
+		if len(v) == 0 {
+			return b
+		}
+		var items []string
+		for _, item := range v {
+			items = append(items, string(item))
+		}
+		rendered = "[" + strings.Join(items, ", ") + "]"
+	default:
+		panic(fmt.Sprintf("export: unsupported value %T for %s", value, name))
+	}
+
+	b.attributes = append(b.attributes, attribute{name: name, value: rendered, multiline: strings.Contains(rendered, "\n")})
+	return b
+}
+
+// AddBlock adds a nested block and returns it.
+func (b *Block) AddBlock(blockType string, labels ...string) *Block {
+	nested := NewBlock(blockType, labels...)
+	b.blocks = append(b.blocks, nested)
+	return nested
+}
+
+// Write writes the blocks as HCL laid out the way terraform fmt would.
+func Write(w io.Writer, blocks []*Block) error {
+	var sb strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		block.write(&sb, 0)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (b *Block) write(sb *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if b.Comment != "" {
+		for _, line := range strings.Split(b.Comment, "\n") {
+			sb.WriteString(indent + "# " + line + "\n")
+		}
+	}
+
+	sb.WriteString(indent + b.Type)
+	for _, label := range b.Labels {
+		sb.WriteString(" " + hclQuote(label))
+	}
+	sb.WriteString(" {\n")
+
+	for start := 0; start < len(b.attributes); {
+		end := start + 1
+		for end < len(b.attributes) && !b.attributes[end-1].multiline && !b.attributes[end].multiline {
+			end++
+		}
+		width := 0
+		for _, attr := range b.attributes[start:end] {
+			if len(attr.name) > width {
+				width = len(attr.name)
+			}
+		}
+		for _, attr := range b.attributes[start:end] {
+			lines := strings.Split(attr.value, "\n")
+			for i := 1; i < len(lines); i++ {
+				if lines[i] != "" {
+					lines[i] = indent + "  " + lines[i]
+				}
+			}
+			sb.WriteString(fmt.Sprintf("%s  %-*s = %s\n", indent, width, attr.name, strings.Join(lines, "\n")))
+		}
+		start = end
+	}
+
+	for i, nested := range b.blocks {
+		if i > 0 || len(b.attributes) > 0 {
+			sb.WriteString("\n")
+		}
+		nested.write(sb, depth+1)
+	}
+
+	sb.WriteString(indent + "}\n")
+}
+
+const heredocMarker = "EOT"
+
+var (
+	heredocMarkerLine = regexp.MustCompile(`(?m)^\s*` + heredocMarker + `\s*$`)
+	// an indented heredoc strips the indentation its lines have in common, so a value needs an unindented line.
+	unindentedLine = regexp.MustCompile(`(?m)^\S`)
+)
+
+// hclString renders a string, values with a trailing newline such as PEM or JSON documents become indented heredocs.
+func hclString(value string) string {
+	if strings.HasSuffix(value, "\n") && strings.Count(value, "\n") > 1 && unindentedLine.MatchString(value) && !heredocMarkerLine.MatchString(value) {
+		body := strings.TrimSuffix(escapeTemplate(value), "\n")
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = "  " + line
+			}
+		}
+		return "<<-" + heredocMarker + "\n" + strings.Join(lines, "\n") + "\n" + heredocMarker
+	}
+	return hclQuote(value)
+}
+
+// hclQuote renders a quoted HCL string, escaping template sequences so the value is taken literally.
+func hclQuote(value string) string {
+	var sb strings.Builder
+	sb.WriteString(`"`)
+	for _, r := range value {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if unicode.IsPrint(r) {
+				sb.WriteRune(r)
+			} else if r > 0xffff {
+				sb.WriteString(fmt.Sprintf(`\U%08x`, r))
+			} else {
+				sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+			}
+		}
+	}
+	sb.WriteString(`"`)
+	return escapeTemplate(sb.String())
+}
+
+func escapeTemplate(value string) string {
+	value = strings.ReplaceAll(value, "${", "$${")
+	return strings.ReplaceAll(value, "%{", "%%{")
+}
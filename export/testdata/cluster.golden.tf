@@ -0,0 +1,256 @@
+variable "api_example_com_private_key" {
+  type      = string
+  sensitive = true
+}
+
+variable "mobile_app_key_auth_key" {
+  type      = string
+  sensitive = true
+}
+
+variable "mobile_app_basic_auth_password" {
+  type      = string
+  sensitive = true
+}
+
+variable "mobile_app_jwt_auth_secret" {
+  type      = string
+  sensitive = true
+}
+
+variable "mobile_app_oauth2_client_secret" {
+  type      = string
+  sensitive = true
+}
+
+resource "kong_certificate" "api_example_com" {
+  certificate = <<-EOT
+    -----BEGIN CERTIFICATE-----
+    MIIBszCCAVmgAwIBAgIUexample
+    -----END CERTIFICATE-----
+  EOT
+  private_key = var.api_example_com_private_key
+  snis        = ["api.example.com"]
+  tags        = ["edge"]
+}
+
+import {
+  to = kong_certificate.api_example_com
+  id = "5f4d1a0e-7a3c-4b8e-9d6b-1c2e3f4a5b01"
+}
+
+resource "kong_service" "orders" {
+  name                  = "orders"
+  protocol              = "https"
+  host                  = "orders.internal"
+  port                  = 443
+  path                  = "/v1"
+  retries               = 5
+  connect_timeout       = 60000
+  write_timeout         = 60000
+  read_timeout          = 60000
+  client_certificate_id = kong_certificate.api_example_com.id
+}
+
+import {
+  to = kong_service.orders
+  id = "0b9f6c1e-3a2d-4c5b-8e7f-6a5b4c3d2e01"
+}
+
+resource "kong_route" "orders_route" {
+  protocols                  = ["http", "https"]
+  methods                    = ["GET", "POST"]
+  hosts                      = ["api.example.com"]
+  paths                      = ["/orders"]
+  path_handling              = "v0"
+  strip_path                 = true
+  preserve_host              = false
+  regex_priority             = 0
+  https_redirect_status_code = 426
+  request_buffering          = true
+  response_buffering         = true
+  service_id                 = kong_service.orders.id
+
+  header {
+    name   = "x-canary"
+    values = ["true"]
+  }
+
+  header {
+    name   = "x-version"
+    values = ["1", "2"]
+  }
+}
+
+import {
+  to = kong_route.orders_route
+  id = "9a8b7c6d-5e4f-4a3b-9c2d-1e0f9a8b7c01"
+}
+
+resource "kong_upstream" "orders_upstream" {
+  name                = "orders.upstream"
+  algorithm           = "round-robin"
+  slots               = 10000
+  hash_on             = "none"
+  hash_fallback       = "none"
+  hash_on_cookie_path = "/"
+
+  healthchecks {
+    threshold = 25
+
+    active {
+      type        = "http"
+      timeout     = 1
+      concurrency = 10
+      http_path   = "/health"
+
+      healthy {
+        interval      = 5
+        http_statuses = [200, 302]
+        successes     = 2
+      }
+
+      unhealthy {
+        interval      = 5
+        http_statuses = [429, 500, 503]
+        tcp_failures  = 2
+        http_failures = 3
+        timeouts      = 2
+      }
+    }
+
+    passive {
+      type = "http"
+
+      healthy {
+        http_statuses = [200, 201]
+        successes     = 5
+      }
+
+      unhealthy {
+        http_statuses = [500, 503]
+        tcp_failures  = 1
+        http_failures = 5
+        timeouts      = 1
+      }
+    }
+  }
+}
+
+import {
+  to = kong_upstream.orders_upstream
+  id = "3c2b1a09-8f7e-4d6c-9b5a-4f3e2d1c0b01"
+}
+
+resource "kong_target" "orders_upstream_10_0_0_1_8080" {
+  target      = "10.0.0.1:8080"
+  weight      = 100
+  upstream_id = kong_upstream.orders_upstream.id
+}
+
+import {
+  to = kong_target.orders_upstream_10_0_0_1_8080
+  id = "3c2b1a09-8f7e-4d6c-9b5a-4f3e2d1c0b01/7e6d5c4b-3a29-4817-8f6e-5d4c3b2a1901"
+}
+
+resource "kong_consumer" "mobile_app" {
+  username  = "mobile-app"
+  custom_id = "123"
+}
+
+import {
+  to = kong_consumer.mobile_app
+  id = "c0ffee00-1111-4222-8333-444455556601"
+}
+
+resource "kong_consumer_key_auth" "mobile_app_key_auth" {
+  consumer_id = kong_consumer.mobile_app.id
+  key         = var.mobile_app_key_auth_key
+}
+
+import {
+  to = kong_consumer_key_auth.mobile_app_key_auth
+  id = "aa11bb22-cc33-4d44-8e55-ff6677889901|c0ffee00-1111-4222-8333-444455556601"
+}
+
+resource "kong_consumer_basic_auth" "mobile_app_basic_auth" {
+  consumer_id = kong_consumer.mobile_app.id
+  username    = "mobile"
+  password    = var.mobile_app_basic_auth_password
+}
+
+import {
+  to = kong_consumer_basic_auth.mobile_app_basic_auth
+  id = "bb22cc33-dd44-4e55-8f66-007788990a01|c0ffee00-1111-4222-8333-444455556601"
+}
+
+resource "kong_consumer_acl" "mobile_app_acl_partners" {
+  consumer_id = kong_consumer.mobile_app.id
+  group       = "partners"
+}
+
+import {
+  to = kong_consumer_acl.mobile_app_acl_partners
+  id = "cc33dd44-ee55-4f66-8077-1188990a0b01|c0ffee00-1111-4222-8333-444455556601"
+}
+
+resource "kong_consumer_jwt_auth" "mobile_app_jwt_auth" {
+  consumer_id    = kong_consumer.mobile_app.id
+  algorithm      = "HS256"
+  key            = "mobile-issuer"
+  rsa_public_key = ""
+  secret         = var.mobile_app_jwt_auth_secret
+}
+
+import {
+  to = kong_consumer_jwt_auth.mobile_app_jwt_auth
+  id = "1b2c3d4e-5f60-4a7b-8c9d-0e1f2a3b4c01|c0ffee00-1111-4222-8333-444455556601"
+}
+
+resource "kong_consumer_oauth2" "mobile_app_oauth2" {
+  consumer_id   = kong_consumer.mobile_app.id
+  name          = "mobile-oauth"
+  client_id     = "mobile-client"
+  client_secret = var.mobile_app_oauth2_client_secret
+  redirect_uris = ["https://mobile.example.com/callback"]
+}
+
+import {
+  to = kong_consumer_oauth2.mobile_app_oauth2
+  id = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c01|c0ffee00-1111-4222-8333-444455556601"
+}
+
+resource "kong_plugin" "orders_rate_limiting" {
+  name       = "rate-limiting"
+  service_id = kong_service.orders.id
+  enabled    = true
+  config_json = <<-EOT
+    {
+      "header_name": "$${x}",
+      "minute": 20,
+      "policy": "local"
+    }
+  EOT
+}
+
+import {
+  to = kong_plugin.orders_rate_limiting
+  id = "dd44ee55-ff66-4077-8188-22990a0b0c01"
+}
+
+resource "kong_plugin" "cors" {
+  name    = "cors"
+  enabled = false
+  config_json = <<-EOT
+    {
+      "origins": [
+        "*"
+      ]
+    }
+  EOT
+}
+
+import {
+  to = kong_plugin.cors
+  id = "ee55ff66-0077-4188-8299-330a0b0c0d01"
+}
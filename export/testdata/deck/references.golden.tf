@@ -0,0 +1,66 @@
+resource "kong_service" "billing" {
+  name     = "billing"
+  protocol = "http"
+  host     = "billing.internal"
+  port     = 8080
+}
+
+resource "kong_route" "billing_route" {
+  name       = "billing-route"
+  protocols  = ["http", "https"]
+  paths      = ["/billing"]
+  service_id = kong_service.billing.id
+}
+
+resource "kong_route" "billing_admin" {
+  name       = "billing-admin"
+  protocols  = ["http", "https"]
+  paths      = ["/billing/admin"]
+  service_id = kong_service.billing.id
+}
+
+resource "kong_route" "external" {
+  name       = "external"
+  protocols  = ["http", "https"]
+  paths      = ["/external"]
+  service_id = "not-in-this-file"
+}
+
+resource "kong_consumer" "finance" {
+  username = "finance"
+}
+
+resource "kong_plugin" "cors" {
+  name = "cors"
+  config_json = <<-EOT
+    {
+      "origins": [
+        "*"
+      ]
+    }
+  EOT
+}
+
+resource "kong_plugin" "billing_admin_finance_rate_limiting" {
+  name        = "rate-limiting"
+  route_id    = kong_route.billing_admin.id
+  consumer_id = kong_consumer.finance.id
+  config_json = <<-EOT
+    {
+      "second": 5
+    }
+  EOT
+}
+
+resource "kong_plugin" "billing_acl" {
+  name       = "acl"
+  service_id = kong_service.billing.id
+  enabled    = false
+  config_json = <<-EOT
+    {
+      "allow": [
+        "finance"
+      ]
+    }
+  EOT
+}
@@ -0,0 +1,225 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+// kongSchemaViolation is the code of the errors whose fields hold a message for every invalid value. The fields of
+// the other errors, such as unique and foreign key violations, hold the offending values.
+const kongSchemaViolation = 2
+
+// kongFieldAttributes are the attributes set from a kong field of another name, for when the resource has no
+// attribute named after the field itself.
+var kongFieldAttributes = map[string]string{
+	"cert":     "certificate",
+	"key":      "private_key",
+	"cert_alt": "certificate_alt",
+	"key_alt":  "private_key_alt",
+	"config":   "config_json",
+}
+
+// kongErrorBody is the structured error the admin api returns.
+type kongErrorBody struct {
+	Code    int                    `json:"code"`
+	Name    string                 `json:"name"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields"`
+}
+
+// kongFieldError is the error of one field, path holds the field names and list indices leading to it.
+type kongFieldError struct {
+	path    []interface{}
+	message string
+}
+
+type kongErrorsKey struct{}
+
+// kongErrors records the error bodies of the admin api calls made with the context returned by recordKongErrors, go-kong
+// only keeps the message of an error.
+type kongErrors struct {
+	mu   sync.Mutex
+	last *kongErrorBody
+}
+
+// recordKongErrors returns a context recording the structured errors of the calls made with it.
+func recordKongErrors(ctx context.Context) (context.Context, *kongErrors) {
+	recorder := &kongErrors{}
+	return context.WithValue(ctx, kongErrorsKey{}, recorder), recorder
+}
+
+// KongErrorRoundTripper hands the error bodies of the admin api to the recorder of the request context, if any.
+type KongErrorRoundTripper struct {
+	rt http.RoundTripper
+}
+
+// RoundTrip satisfies the RoundTripper interface.
+func (t *KongErrorRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(req)
+	recorder, ok := req.Context().Value(kongErrorsKey{}).(*kongErrors)
+	if err != nil || !ok || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var kongError kongErrorBody
+	if json.Unmarshal(body, &kongError) == nil {
+		recorder.mu.Lock()
+		recorder.last = &kongError
+		recorder.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// diagnostics turns the field errors of the last error kong returned into a diagnostic per field, pointing at the
+// attribute of resourceSchema the field was set from. err is returned as a single diagnostic when it is not an admin
+// api error or kong gave no field errors.
+func (e *kongErrors) diagnostics(summary string, resourceSchema map[string]*schema.Schema, err error) diag.Diagnostics {
+	e.mu.Lock()
+	last := e.last
+	e.mu.Unlock()
+
+	var apiErr *kong.APIError
+	if last == nil || len(last.Fields) == 0 || !errors.As(err, &apiErr) {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	for _, fieldError := range last.fieldErrors() {
+		detail := fieldError.message
+		if name, ok := fieldError.path[0].(string); !ok || !strings.HasPrefix(name, "@") {
+			detail = fmt.Sprintf("%s: %s", kongFieldName(fieldError.path), fieldError.message)
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       summary,
+			Detail:        detail,
+			AttributePath: kongAttributePath(resourceSchema, fieldError.path),
+		})
+	}
+	return diags
+}
+
+// fieldErrors flattens the fields of the error, sorted by field so the diagnostics are stable.
+func (b *kongErrorBody) fieldErrors() []kongFieldError {
+	var fieldErrors []kongFieldError
+	for _, name := range sortedFieldNames(b.Fields) {
+		if b.Code != kongSchemaViolation {
+			fieldErrors = append(fieldErrors, kongFieldError{path: []interface{}{name}, message: b.Message})
+			continue
+		}
+		fieldErrors = appendKongFieldErrors(fieldErrors, []interface{}{name}, b.Fields[name])
+	}
+	return fieldErrors
+}
+
+func appendKongFieldErrors(fieldErrors []kongFieldError, path []interface{}, value interface{}) []kongFieldError {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range sortedFieldNames(value) {
+			fieldErrors = appendKongFieldErrors(fieldErrors, append(path[:len(path):len(path)], name), value[name])
+		}
+	case []interface{}:
+		for i, element := range value {
+			if element != nil {
+				fieldErrors = appendKongFieldErrors(fieldErrors, append(path[:len(path):len(path)], i), element)
+			}
+		}
+	case nil:
+	default:
+		fieldErrors = append(fieldErrors, kongFieldError{path: path, message: fmt.Sprint(value)})
+	}
+	return fieldErrors
+}
+
+func sortedFieldNames(fields map[string]interface{}) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func kongFieldName(path []interface{}) string {
+	steps := make([]string, len(path))
+	for i, step := range path {
+		if index, ok := step.(int); ok {
+			steps[i] = strconv.Itoa(index)
+		} else {
+			steps[i] = step.(string)
+		}
+	}
+	return strings.Join(steps, ".")
+}
+
+// kongAttributePath follows a kong field path through resourceSchema as far as it has attributes for it. Blocks of at
+// most one element are stepped into at index 0, sets and maps cannot be indexed so the path stops at them. It returns
+// nil when not even the top level field is an attribute.
+func kongAttributePath(resourceSchema map[string]*schema.Schema, fieldPath []interface{}) cty.Path {
+	var path cty.Path
+	current := resourceSchema
+	// list is the list attribute last stepped into, a following index selects one of its elements.
+	var list *schema.Schema
+	for _, step := range fieldPath {
+		switch step := step.(type) {
+		case string:
+			name, ok := kongFieldAttribute(current, step)
+			if !ok {
+				return path
+			}
+			attribute := current[name]
+			path, current, list = path.GetAttr(name), nil, nil
+			if attribute.Type != schema.TypeList {
+				continue
+			}
+			if block, ok := attribute.Elem.(*schema.Resource); ok && attribute.MaxItems == 1 {
+				path, current = path.IndexInt(0), block.Schema
+			} else {
+				list = attribute
+			}
+		case int:
+			if list == nil {
+				return path
+			}
+			path = path.IndexInt(step)
+			if block, ok := list.Elem.(*schema.Resource); ok {
+				current = block.Schema
+			}
+			list = nil
+		}
+	}
+	return path
+}
+
+// kongFieldAttribute returns the attribute a kong field is set from: the attribute of the same name, the id attribute
+// of a foreign key such as service_id, the singular attribute of a list such as source or a renamed attribute.
+func kongFieldAttribute(resourceSchema map[string]*schema.Schema, field string) (string, bool) {
+	candidates := []string{field, field + "_id", strings.TrimSuffix(field, "s"), kongFieldAttributes[field]}
+	for _, candidate := range candidates {
+		if _, ok := resourceSchema[candidate]; ok && candidate != "" {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,137 @@
+package kong
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestKongAttributePath(t *testing.T) {
+	schemas := map[string]map[string]*schema.Schema{
+		"route":       resourceKongRoute().Schema,
+		"upstream":    resourceKongUpstream().Schema,
+		"certificate": resourceKongCertificate().Schema,
+		"plugin":      resourceKongPlugin().Schema,
+	}
+	tests := []struct {
+		schema    string
+		fieldPath []interface{}
+		path      cty.Path
+	}{
+		{schema: "route", fieldPath: []interface{}{"paths", 1}, path: cty.GetAttrPath("paths").IndexInt(1)},
+		{schema: "route", fieldPath: []interface{}{"service", "id"}, path: cty.GetAttrPath("service_id")},
+		{schema: "route", fieldPath: []interface{}{"sources", 0, "ip"}, path: cty.GetAttrPath("source")},
+		{schema: "route", fieldPath: []interface{}{"@entity", 0}, path: nil},
+		{schema: "upstream", fieldPath: []interface{}{"healthchecks", "active", "healthy", "interval"}, path: cty.GetAttrPath("healthchecks").IndexInt(0).GetAttr("active").IndexInt(0).GetAttr("healthy").IndexInt(0).GetAttr("interval")},
+		{schema: "certificate", fieldPath: []interface{}{"key"}, path: cty.GetAttrPath("private_key")},
+		{schema: "plugin", fieldPath: []interface{}{"config", "minute"}, path: cty.GetAttrPath("config_json")},
+	}
+
+	for _, test := range tests {
+		if path := kongAttributePath(schemas[test.schema], test.fieldPath); !path.Equals(test.path) {
+			t.Errorf("expected %s field %v to map to %#v, got %#v", test.schema, test.fieldPath, test.path, path)
+		}
+	}
+}
+
+func TestKongErrorDiagnostics(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	client, err := GetKongClient(Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceSchema := resourceKongService().Schema
+	routeSchema := resourceKongRoute().Schema
+
+	if _, err := client.Services.Create(context.Background(), &kong.Service{Name: kong.String("taken"), Host: kong.String("example.com")}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, kongErrors := recordKongErrors(context.Background())
+	_, err = client.Services.Create(ctx, &kong.Service{Name: kong.String("taken"), Host: kong.String("example.com")})
+	diags := kongErrors.diagnostics("failed to create kong service", serviceSchema, err)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("name")) {
+		t.Errorf("expected the unique violation to point at name, got %v", diags)
+	}
+
+	ctx, kongErrors = recordKongErrors(context.Background())
+	_, err = client.Routes.Create(ctx, &kong.Route{Protocols: kong.StringSlice("http"), Service: &kong.Service{ID: kong.String("6e4a2c9a-53a5-4a5a-a6c3-3d1fbbd7e0e7")}, Paths: kong.StringSlice("/")})
+	diags = kongErrors.diagnostics("failed to create kong route", routeSchema, err)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("service_id")) {
+		t.Errorf("expected the foreign key violation to point at service_id, got %v", diags)
+	}
+
+	ctx, kongErrors = recordKongErrors(context.Background())
+	_, err = client.Routes.Create(ctx, &kong.Route{Protocols: kong.StringSlice("http")})
+	diags = kongErrors.diagnostics("failed to create kong route", routeSchema, err)
+	if len(diags) != 1 || diags[0].AttributePath != nil || diags[0].Severity != diag.Error {
+		t.Errorf("expected an entity error without an attribute path, got %v", diags)
+	}
+
+	// an error kong returned no fields for is passed through as it is.
+	ctx, kongErrors = recordKongErrors(context.Background())
+	_, err = client.Services.Get(ctx, kong.String("missing"))
+	diags = kongErrors.diagnostics("failed to read kong service", serviceSchema, err)
+	if len(diags) != 1 || diags[0].Summary != err.Error() {
+		t.Errorf("expected the not found error as it is, got %v", diags)
+	}
+}
+
+func TestResourceCreateErrorsPointAtAttributes(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if _, err := p.Meta().(*config).adminClient.Services.Create(context.Background(), &kong.Service{Name: kong.String("taken"), Host: kong.String("example.com")}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		resource string
+		raw      map[string]interface{}
+		path     cty.Path
+	}{
+		"unique service name":   {"kong_service", map[string]interface{}{"name": "taken", "protocol": "http", "host": "example.com"}, cty.GetAttrPath("name")},
+		"missing route service": {"kong_route", map[string]interface{}{"protocols": []interface{}{"http"}, "paths": []interface{}{"/"}, "service_id": "6e4a2c9a-53a5-4a5a-a6c3-3d1fbbd7e0e7"}, cty.GetAttrPath("service_id")},
+	}
+	for name, test := range tests {
+		resource := p.ResourcesMap[test.resource]
+		d := schema.TestResourceDataRaw(t, resource.Schema, test.raw)
+		diags := resource.CreateContext(context.Background(), d, p.Meta())
+		if len(diags) != 1 || !diags[0].AttributePath.Equals(test.path) {
+			t.Errorf("%s: expected the error to point at %v, got %v", name, test.path, diags)
+		}
+	}
+}
+
+func TestKongSchemaViolationFieldErrors(t *testing.T) {
+	body := kongErrorBody{
+		Code: kongSchemaViolation,
+		Fields: map[string]interface{}{
+			"paths":  []interface{}{nil, "should start with: /"},
+			"config": map[string]interface{}{"minute": "expected a number", "hour": "expected a number"},
+		},
+	}
+
+	fieldErrors := body.fieldErrors()
+	expected := []string{"config.hour", "config.minute", "paths.1"}
+	if len(fieldErrors) != len(expected) {
+		t.Fatalf("expected %d field errors, got %v", len(expected), fieldErrors)
+	}
+	for i, fieldError := range fieldErrors {
+		if name := kongFieldName(fieldError.path); name != expected[i] {
+			t.Errorf("expected field error %d to be for %s, got %s", i, expected[i], name)
+		}
+	}
+}
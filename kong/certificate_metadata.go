@@ -0,0 +1,110 @@
+package kong
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var certificateMetadataProperties = []string{"not_before", "not_after", "subject", "issuer", "dns_names", "serial_number", "fingerprint_sha256"}
+
+// parseCertificatePEM returns the first certificate of a PEM bundle, which for kong is the leaf certificate.
+func parseCertificatePEM(certificatePEM string) (*x509.Certificate, error) {
+	rest := []byte(certificatePEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// flattenCertificateMetadata maps a parsed certificate onto the computed attributes of kong_certificate,
+// a nil certificate gives empty values.
+func flattenCertificateMetadata(cert *x509.Certificate) map[string]interface{} {
+	if cert == nil {
+		return map[string]interface{}{
+			"not_before":         "",
+			"not_after":          "",
+			"subject":            "",
+			"issuer":             "",
+			"dns_names":          []string{},
+			"serial_number":      "",
+			"fingerprint_sha256": "",
+		}
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	dnsNames := cert.DNSNames
+	if dnsNames == nil {
+		dnsNames = []string{}
+	}
+
+	return map[string]interface{}{
+		"not_before":         cert.NotBefore.UTC().Format(time.RFC3339),
+		"not_after":          cert.NotAfter.UTC().Format(time.RFC3339),
+		"subject":            cert.Subject.String(),
+		"issuer":             cert.Issuer.String(),
+		"dns_names":          dnsNames,
+		"serial_number":      cert.SerialNumber.String(),
+		"fingerprint_sha256": hex.EncodeToString(fingerprint[:]),
+	}
+}
+
+// validateCertificate checks a certificate is usable before it is sent to kong: it has not expired, it matches the
+// private key (when one is given) and its subject alternative names cover every sni.
+func validateCertificate(certificatePEM string, privateKeyPEM string, snis []string, now time.Time) error {
+	// a vault reference is only resolved by kong, there is nothing to check before then
+	if isVaultReference(certificatePEM) {
+		return nil
+	}
+
+	cert, err := parseCertificatePEM(certificatePEM)
+	if err != nil {
+		return fmt.Errorf("could not parse certificate: %v", err)
+	}
+
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("certificate %s expired on %s", cert.Subject, cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("certificate %s is not valid before %s", cert.Subject, cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+
+	if privateKeyPEM != "" && !isVaultReference(privateKeyPEM) {
+		if _, err := tls.X509KeyPair([]byte(certificatePEM), []byte(privateKeyPEM)); err != nil {
+			return fmt.Errorf("certificate %s does not match private_key: %v", cert.Subject, err)
+		}
+	}
+
+	var uncovered []string
+	for _, sni := range snis {
+		if !certificateCoversName(cert, sni) {
+			uncovered = append(uncovered, sni)
+		}
+	}
+	if len(uncovered) > 0 {
+		return fmt.Errorf("certificate %s subject alternative names %v do not cover snis: %s", cert.Subject, cert.DNSNames, strings.Join(uncovered, ", "))
+	}
+
+	return nil
+}
+
+func certificateCoversName(cert *x509.Certificate, name string) bool {
+	// a wildcard sni is covered by the identical wildcard san, VerifyHostname handles everything else
+	for _, dnsName := range cert.DNSNames {
+		if strings.EqualFold(dnsName, name) {
+			return true
+		}
+	}
+	return cert.VerifyHostname(name) == nil
+}
@@ -0,0 +1,120 @@
+package kong
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func generateTestCertificate(t *testing.T, notBefore time.Time, notAfter time.Time, dnsNames ...string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com", Organization: []string{"kong"}},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		DNSNames:     dnsNames,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestFlattenCertificateMetadata(t *testing.T) {
+	notBefore := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+	certPEM, _ := generateTestCertificate(t, notBefore, notAfter, "example.com", "*.example.com")
+
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := flattenCertificateMetadata(cert)
+
+	expected := map[string]interface{}{
+		"not_before":    "2021-01-01T00:00:00Z",
+		"not_after":     "2031-01-01T00:00:00Z",
+		"subject":       "CN=example.com,O=kong",
+		"issuer":        "CN=example.com,O=kong",
+		"dns_names":     []string{"example.com", "*.example.com"},
+		"serial_number": "42",
+	}
+	for key, value := range expected {
+		if !reflect.DeepEqual(out[key], value) {
+			t.Errorf("Error matching %s: %#v vs %#v", key, out[key], value)
+		}
+	}
+	if len(out["fingerprint_sha256"].(string)) != 64 {
+		t.Errorf("Expected a hex encoded sha256 fingerprint got %s", out["fingerprint_sha256"])
+	}
+
+	empty := flattenCertificateMetadata(nil)
+	for _, key := range certificateMetadataProperties {
+		if _, ok := empty[key]; !ok {
+			t.Errorf("Expected %s to be cleared", key)
+		}
+	}
+}
+
+func TestParseCertificatePEMSkipsKeys(t *testing.T) {
+	certPEM, keyPEM := generateTestCertificate(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), "example.com")
+
+	if _, err := parseCertificatePEM(keyPEM + certPEM); err != nil {
+		t.Fatalf("expected certificate to be found after the key: %v", err)
+	}
+	if _, err := parseCertificatePEM("{vault://env/my-cert}"); err == nil {
+		t.Fatal("expected an error parsing a non PEM value")
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM := generateTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour), "example.com", "*.api.example.com")
+	expiredPEM, expiredKeyPEM := generateTestCertificate(t, now.Add(-2*time.Hour), now.Add(-time.Hour), "example.com")
+	_, otherKeyPEM := generateTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour), "example.com")
+
+	cases := []struct {
+		name     string
+		cert     string
+		key      string
+		snis     []string
+		expected string
+	}{
+		{name: "valid", cert: certPEM, key: keyPEM, snis: []string{"example.com", "foo.api.example.com", "*.api.example.com"}},
+		{name: "no private key", cert: certPEM, snis: []string{"example.com"}},
+		{name: "expired", cert: expiredPEM, key: expiredKeyPEM, expected: "expired on"},
+		{name: "mismatched key", cert: certPEM, key: otherKeyPEM, expected: "does not match private_key"},
+		{name: "uncovered sni", cert: certPEM, key: keyPEM, snis: []string{"example.com", "other.com"}, expected: "do not cover snis: other.com"},
+		{name: "not a certificate", cert: "not a certificate", expected: "could not parse certificate"},
+	}
+
+	for _, c := range cases {
+		err := validateCertificate(c.cert, c.key, c.snis, now)
+		if c.expected == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		}
+		if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Errorf("%s: expected error containing %q got %v", c.name, c.expected, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// kongConsumerGroup is a Kong Enterprise consumer group, go-kong does not model consumer groups yet.
+type kongConsumerGroup struct {
+	CreatedAt *int      `json:"created_at,omitempty"`
+	ID        *string   `json:"id,omitempty"`
+	Name      *string   `json:"name,omitempty"`
+	Tags      []*string `json:"tags,omitempty"`
+}
+
+// kongConsumerGroupObject is what kong returns when fetching a single consumer group, the group together with its
+// members and plugin overrides.
+type kongConsumerGroupObject struct {
+	ConsumerGroup *kongConsumerGroup         `json:"consumer_group,omitempty"`
+	Consumers     []*kong.Consumer           `json:"consumers,omitempty"`
+	Plugins       []*kongConsumerGroupPlugin `json:"plugins,omitempty"`
+}
+
+// kongConsumerGroupPlugin is a plugin configuration override applied to every member of a consumer group.
+type kongConsumerGroupPlugin struct {
+	ID            *string            `json:"id,omitempty"`
+	Name          *string            `json:"name,omitempty"`
+	Config        kong.Configuration `json:"config,omitempty"`
+	ConsumerGroup *kongConsumerGroup `json:"consumer_group,omitempty"`
+}
+
+// kongPlugin adds the consumer group scope that go-kong does not model yet. It is only sent for plugins scoped to a
+// consumer group, or being unscoped from one, where a nil consumer group is sent as null.
+type kongPlugin struct {
+	*kong.Plugin
+	ConsumerGroup *kongConsumerGroup `json:"consumer_group"`
+}
+
+func createKongConsumerGroup(ctx context.Context, client *kong.Client, group *kongConsumerGroup) (*kongConsumerGroup, error) {
+	created := &kongConsumerGroup{}
+	if err := doKongRequest(ctx, client, "POST", "/consumer_groups", group, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func updateKongConsumerGroup(ctx context.Context, client *kong.Client, group *kongConsumerGroup) (*kongConsumerGroup, error) {
+	if group.ID == nil {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	updated := &kongConsumerGroup{}
+	if err := doKongRequest(ctx, client, "PATCH", consumerGroupEndpoint(*group.ID), group, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func getKongConsumerGroup(ctx context.Context, client *kong.Client, groupNameOrID string) (*kongConsumerGroupObject, error) {
+	group := &kongConsumerGroupObject{}
+	if err := doKongRequest(ctx, client, "GET", consumerGroupEndpoint(groupNameOrID), nil, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func deleteKongConsumerGroup(ctx context.Context, client *kong.Client, groupNameOrID string) error {
+	return doKongRequest(ctx, client, "DELETE", consumerGroupEndpoint(groupNameOrID), nil, nil)
+}
+
+func addKongConsumerGroupMember(ctx context.Context, client *kong.Client, groupNameOrID string, consumerID string) error {
+	body := map[string]string{"consumer": consumerID}
+	return doKongRequest(ctx, client, "POST", consumerGroupEndpoint(groupNameOrID)+"/consumers", body, nil)
+}
+
+func removeKongConsumerGroupMember(ctx context.Context, client *kong.Client, groupNameOrID string, consumerID string) error {
+	endpoint := fmt.Sprintf("%s/consumers/%s", consumerGroupEndpoint(groupNameOrID), url.PathEscape(consumerID))
+	return doKongRequest(ctx, client, "DELETE", endpoint, nil, nil)
+}
+
+func putKongConsumerGroupPlugin(ctx context.Context, client *kong.Client, groupNameOrID string, plugin *kongConsumerGroupPlugin) (*kongConsumerGroupPlugin, error) {
+	if plugin.Name == nil {
+		return nil, fmt.Errorf("Name cannot be nil for consumer group plugin")
+	}
+	body := map[string]interface{}{"config": plugin.Config}
+	updated := &kongConsumerGroupPlugin{}
+	if err := doKongRequest(ctx, client, "PUT", consumerGroupPluginEndpoint(groupNameOrID, *plugin.Name), body, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func getKongConsumerGroupPlugin(ctx context.Context, client *kong.Client, groupNameOrID string, name string) (*kongConsumerGroupPlugin, error) {
+	plugin := &kongConsumerGroupPlugin{}
+	if err := doKongRequest(ctx, client, "GET", consumerGroupPluginEndpoint(groupNameOrID, name), nil, plugin); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
+
+func deleteKongConsumerGroupPlugin(ctx context.Context, client *kong.Client, groupNameOrID string, name string) error {
+	return doKongRequest(ctx, client, "DELETE", consumerGroupPluginEndpoint(groupNameOrID, name), nil, nil)
+}
+
+func createKongPlugin(ctx context.Context, client *kong.Client, plugin *kongPlugin) (*kongPlugin, error) {
+	created := &kongPlugin{Plugin: &kong.Plugin{}}
+	if err := doKongRequest(ctx, client, "POST", "/plugins", plugin, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func updateKongPlugin(ctx context.Context, client *kong.Client, plugin *kongPlugin) (*kongPlugin, error) {
+	if plugin.ID == nil {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	updated := &kongPlugin{Plugin: &kong.Plugin{}}
+	if err := doKongRequest(ctx, client, "PATCH", fmt.Sprintf("/plugins/%v", *plugin.ID), plugin, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func getKongPlugin(ctx context.Context, client *kong.Client, pluginID string) (*kongPlugin, error) {
+	plugin := &kongPlugin{Plugin: &kong.Plugin{}}
+	if err := doKongRequest(ctx, client, "GET", fmt.Sprintf("/plugins/%v", pluginID), nil, plugin); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
+
+func consumerGroupEndpoint(groupNameOrID string) string {
+	return "/consumer_groups/" + url.PathEscape(groupNameOrID)
+}
+
+func consumerGroupPluginEndpoint(groupNameOrID string, name string) string {
+	return fmt.Sprintf("%s/overrides/plugins/%s", consumerGroupEndpoint(groupNameOrID), url.PathEscape(name))
+}
@@ -0,0 +1,78 @@
+package containers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+)
+
+// cassandraStartupTimeout is how long cassandra is given to accept queries, it takes a lot longer to start than
+// postgres.
+const cassandraStartupTimeout = 3 * time.Minute
+
+type cassandraContainer struct {
+	Name     string
+	Keyspace string
+	pool     *dockertest.Pool
+	resource *dockertest.Resource
+}
+
+func NewCassandraContainer(pool *dockertest.Pool) *cassandraContainer {
+
+	resource, err := pool.Run("cassandra", "3.11", []string{
+		"MAX_HEAP_SIZE=512M",
+		"HEAP_NEWSIZE=128M",
+	})
+
+	if err != nil {
+		log.Fatalf("Could not start resource: %s", err)
+	}
+
+	containerName := getContainerName(resource)
+
+	maxWait := pool.MaxWait
+	pool.MaxWait = cassandraStartupTimeout
+	defer func() { pool.MaxWait = maxWait }()
+
+	if err = pool.Retry(func() error {
+		exitCode, err := resource.Exec([]string{"cqlsh", "-e", "describe keyspaces"}, dockertest.ExecOptions{})
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			log.Printf("Cassandra (%v): waiting for cqlsh", containerName)
+			return errors.New(fmt.Sprintf("Cassandra (%v): not accepting queries yet", containerName))
+		}
+		return nil
+	}); err != nil {
+		log.Fatalf("Could not connect to cassandra: %s", err)
+	}
+
+	log.Printf("Cassandra (%v): up", containerName)
+
+	return &cassandraContainer{
+		Name:     containerName,
+		Keyspace: "kong",
+		pool:     pool,
+		resource: resource,
+	}
+}
+
+func (cassandra *cassandraContainer) Stop() error {
+	return cassandra.pool.Purge(cassandra.resource)
+}
+
+func (cassandra *cassandraContainer) kongEnv() []string {
+	return []string{
+		fmt.Sprintf("KONG_DATABASE=%s", DatabaseCassandra),
+		fmt.Sprintf("KONG_CASSANDRA_CONTACT_POINTS=%s", cassandra.Name),
+		fmt.Sprintf("KONG_CASSANDRA_KEYSPACE=%s", cassandra.Keyspace),
+	}
+}
+
+func (cassandra *cassandraContainer) containerName() string {
+	return cassandra.Name
+}
@@ -13,3 +13,11 @@ func getContainerName(container *dockertest.Resource) string {
 type container interface {
 	Stop() error
 }
+
+// kongDatabase is a container kong stores its configuration in.
+type kongDatabase interface {
+	container
+	// kongEnv returns the environment variables pointing kong at the database.
+	kongEnv() []string
+	containerName() string
+}
@@ -16,56 +16,35 @@ type kongContainer struct {
 	HostAddress string
 }
 
-func NewKongContainer(pool *dockertest.Pool, postgres *postgresContainer, kongRepository string, kongVersion string, kongLicense string) *kongContainer {
+// NewKongContainer starts kong storing its configuration in database, after bootstrapping its migrations. Kong runs in
+// DB-less mode when database is nil.
+func NewKongContainer(pool *dockertest.Pool, database kongDatabase, kongRepository string, kongVersion string, kongLicense string) *kongContainer {
 
 	envVars := []string{
-		"KONG_DATABASE=postgres",
 		"KONG_ADMIN_LISTEN=0.0.0.0:8001",
 		fmt.Sprintf("KONG_LICENSE_DATA=%s", kongLicense),
-		fmt.Sprintf("KONG_PG_HOST=%s", postgres.Name),
-		fmt.Sprintf("KONG_PG_USER=%s", postgres.DatabaseUser),
-		fmt.Sprintf("KONG_PG_PASSWORD=%s", postgres.Password),
+	}
+	var links []string
+	if database == nil {
+		envVars = append(envVars, "KONG_DATABASE=off")
+	} else {
+		envVars = append(envVars, database.kongEnv()...)
+		links = []string{database.containerName()}
+		runMigrations(pool, envVars, links, kongRepository, kongVersion)
 	}
 
 	options := &dockertest.RunOptions{
 		Repository: kongRepository,
 		Tag:        kongVersion,
 		Env:        envVars,
-		Links:      []string{postgres.Name},
-		Cmd:        []string{"kong", "migrations", "bootstrap"},
-	}
-
-	migrations, err := pool.RunWithOptions(options)
-
-	if err := pool.Retry(func() error {
-		migrationsContainer, err := pool.Client.InspectContainer(migrations.Container.ID)
-		migrationsContainerName := getContainerName(migrations)
-		if err != nil {
-			log.Fatalf("Could not get state of migrations container %v", err)
-		}
-
-		if migrationsContainer.State.Running {
-			log.Printf("Kong Migrations (%v): waiting for migration", migrationsContainerName)
-			return errors.New(fmt.Sprintf("Kong Migrations (%v): Error waiting for migration to finish", migrationsContainerName))
-		}
-		return nil
-	}); err != nil {
-		log.Fatalf("Could not connect to kong: %s", err)
+		Links:      links,
 	}
 
+	resource, err := pool.RunWithOptions(options)
 	if err != nil {
 		log.Fatalf("Could not start kong: %s", err)
 	}
 
-	options = &dockertest.RunOptions{
-		Repository: kongRepository,
-		Tag:        kongVersion,
-		Env:        envVars,
-		Links:      []string{fmt.Sprintf("%s:postgres", postgres.Name)},
-	}
-
-	resource, err := pool.RunWithOptions(options)
-
 	kongContainerName := getContainerName(resource)
 
 	kongAddress := fmt.Sprintf("http://localhost:%v", resource.GetPort("8001/tcp"))
@@ -101,6 +80,41 @@ func NewKongContainer(pool *dockertest.Pool, postgres *postgresContainer, kongRe
 	}
 }
 
+// runMigrations bootstraps kong's database and waits for the migrations to finish, a failed bootstrap stops the tests.
+func runMigrations(pool *dockertest.Pool, envVars []string, links []string, kongRepository string, kongVersion string) {
+	options := &dockertest.RunOptions{
+		Repository: kongRepository,
+		Tag:        kongVersion,
+		Env:        envVars,
+		Links:      links,
+		Cmd:        []string{"kong", "migrations", "bootstrap"},
+	}
+
+	migrations, err := pool.RunWithOptions(options)
+	if err != nil {
+		log.Fatalf("Could not start kong migrations: %s", err)
+	}
+	migrationsContainerName := getContainerName(migrations)
+
+	if err := pool.Retry(func() error {
+		migrationsContainer, err := pool.Client.InspectContainer(migrations.Container.ID)
+		if err != nil {
+			log.Fatalf("Could not get state of migrations container %v", err)
+		}
+
+		if migrationsContainer.State.Running {
+			log.Printf("Kong Migrations (%v): waiting for migration", migrationsContainerName)
+			return errors.New(fmt.Sprintf("Kong Migrations (%v): Error waiting for migration to finish", migrationsContainerName))
+		}
+		if migrationsContainer.State.ExitCode != 0 {
+			log.Fatalf("Kong Migrations (%v): failed with exit code %d", migrationsContainerName, migrationsContainer.State.ExitCode)
+		}
+		return nil
+	}); err != nil {
+		log.Fatalf("Could not connect to kong: %s", err)
+	}
+}
+
 func (kong *kongContainer) Stop() error {
 	return kong.pool.Purge(kong.resource)
 }
@@ -69,3 +69,16 @@ func NewPostgresContainer(pool *dockertest.Pool) *postgresContainer {
 func (postgres *postgresContainer) Stop() error {
 	return postgres.pool.Purge(postgres.resource)
 }
+
+func (postgres *postgresContainer) kongEnv() []string {
+	return []string{
+		fmt.Sprintf("KONG_DATABASE=%s", DatabasePostgres),
+		fmt.Sprintf("KONG_PG_HOST=%s", postgres.Name),
+		fmt.Sprintf("KONG_PG_USER=%s", postgres.DatabaseUser),
+		fmt.Sprintf("KONG_PG_PASSWORD=%s", postgres.Password),
+	}
+}
+
+func (postgres *postgresContainer) containerName() string {
+	return postgres.Name
+}
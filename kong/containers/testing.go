@@ -3,28 +3,82 @@ package containers
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/ory/dockertest/v3"
 )
 
+// The storage modes kong can run in, they match the values of KONG_DATABASE.
+const (
+	DatabasePostgres  = "postgres"
+	DatabaseCassandra = "cassandra"
+	DatabaseOff       = "off"
+)
+
 type TestContext struct {
 	containers      []container
 	KongHostAddress string
+	// Database is the storage mode kong runs in, one of DatabasePostgres, DatabaseCassandra or DatabaseOff.
+	Database string
 }
 
+// StartKong starts kong backed by postgres.
 func StartKong(kongRepository string, kongVersion string, kongLicense string) *TestContext {
+	pool := newPool()
+
+	postgres := NewPostgresContainer(pool)
+	kong := NewKongContainer(pool, postgres, kongRepository, kongVersion, kongLicense)
+
+	return &TestContext{containers: []container{postgres, kong}, KongHostAddress: kong.HostAddress, Database: DatabasePostgres}
+}
+
+// StartKongDBLess starts kong without a database, its configuration can only be set through /config.
+func StartKongDBLess(kongRepository string, kongVersion string, kongLicense string) *TestContext {
+	pool := newPool()
+
+	kong := NewKongContainer(pool, nil, kongRepository, kongVersion, kongLicense)
+
+	return &TestContext{containers: []container{kong}, KongHostAddress: kong.HostAddress, Database: DatabaseOff}
+}
+
+// StartKongCassandra starts kong backed by cassandra. Kong 3.0 dropped cassandra so newer versions stop the tests
+// straight away rather than failing the migrations.
+func StartKongCassandra(kongRepository string, kongVersion string, kongLicense string) *TestContext {
+	if major, err := strconv.Atoi(strings.SplitN(kongVersion, ".", 2)[0]); err == nil && major >= 3 {
+		log.Fatalf("Kong %s does not support cassandra, it was removed in Kong 3.0", kongVersion)
+	}
+
+	pool := newPool()
+
+	cassandra := NewCassandraContainer(pool)
+	kong := NewKongContainer(pool, cassandra, kongRepository, kongVersion, kongLicense)
+
+	return &TestContext{containers: []container{cassandra, kong}, KongHostAddress: kong.HostAddress, Database: DatabaseCassandra}
+}
+
+// StartKongWithDatabase starts kong in the given storage mode.
+func StartKongWithDatabase(database string, kongRepository string, kongVersion string, kongLicense string) *TestContext {
+	switch database {
+	case DatabasePostgres:
+		return StartKong(kongRepository, kongVersion, kongLicense)
+	case DatabaseCassandra:
+		return StartKongCassandra(kongRepository, kongVersion, kongLicense)
+	case DatabaseOff:
+		return StartKongDBLess(kongRepository, kongVersion, kongLicense)
+	}
+	log.Fatalf("Unknown kong database %q, expected one of %s, %s or %s", database, DatabasePostgres, DatabaseCassandra, DatabaseOff)
+	return nil
+}
+
+func newPool() *dockertest.Pool {
 	log.SetOutput(os.Stdout)
 
-	var err error
 	pool, err := dockertest.NewPool("")
 	if err != nil {
 		log.Fatalf("Could not connect to docker: %s", err)
 	}
-
-	postgres := NewPostgresContainer(pool)
-	kong := NewKongContainer(pool, postgres, kongRepository, kongVersion, kongLicense)
-
-	return &TestContext{containers: []container{postgres, kong}, KongHostAddress: kong.HostAddress}
+	return pool
 }
 
 func StopKong(testContext *TestContext) {
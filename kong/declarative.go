@@ -0,0 +1,624 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultDeclarativeFormatVersion = "2.1"
+
+// declarativeFlushDelay is how long the configuration waits for more writes before it is posted, and
+// declarativeMaxFlushDelay how long a write waits at most.
+const (
+	declarativeFlushDelay    = 200 * time.Millisecond
+	declarativeMaxFlushDelay = 2 * time.Second
+)
+
+// declarativeEntity describes how an admin API collection is written in a declarative configuration.
+type declarativeEntity struct {
+	// key is the top level key of the collection in the declarative configuration.
+	key string
+	// listPath is where existing entities are listed, empty when they are listed per parent.
+	listPath string
+	// nameField is the unique field an entity can be addressed by instead of its id.
+	nameField string
+}
+
+// declarativeEntities are the collections kept in the declarative configuration, keyed by their admin API path.
+var declarativeEntities = map[string]declarativeEntity{
+	"services":        {key: "services", listPath: "/services", nameField: "name"},
+	"routes":          {key: "routes", listPath: "/routes", nameField: "name"},
+	"consumers":       {key: "consumers", listPath: "/consumers", nameField: "username"},
+	"plugins":         {key: "plugins", listPath: "/plugins"},
+	"upstreams":       {key: "upstreams", listPath: "/upstreams", nameField: "name"},
+	"targets":         {key: "targets", nameField: "target"},
+	"certificates":    {key: "certificates", listPath: "/certificates"},
+	"ca_certificates": {key: "ca_certificates", listPath: "/ca_certificates"},
+	"key-auth":        {key: "keyauth_credentials", listPath: "/key-auths", nameField: "key"},
+	"basic-auth":      {key: "basicauth_credentials", listPath: "/basic-auths", nameField: "username"},
+	"acls":            {key: "acls", listPath: "/acls", nameField: "group"},
+	"jwt":             {key: "jwt_secrets", listPath: "/jwts", nameField: "key"},
+	"oauth2":          {key: "oauth2_credentials", listPath: "/oauth2", nameField: "client_id"},
+	"hmac-auth":       {key: "hmacauth_credentials", listPath: "/hmac-auths", nameField: "username"},
+	"vaults":          {key: "vaults", listPath: "/vaults", nameField: "prefix"},
+	"key-sets":        {key: "key_sets", listPath: "/key-sets", nameField: "name"},
+	"keys":            {key: "keys", listPath: "/keys", nameField: "name"},
+}
+
+// declarativeParents maps the parent collection of a nested path such as /consumers/{id}/key-auth onto the foreign
+// key of the child entity.
+var declarativeParents = map[string]string{
+	"services":  "service",
+	"routes":    "route",
+	"consumers": "consumer",
+	"upstreams": "upstream",
+}
+
+// declarativeOmittedFields are set by kong and left out of the configuration sent to /config.
+var declarativeOmittedFields = []string{"created_at", "updated_at"}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// DeclarativeRoundTripper lets the provider manage a DB-less Kong. Entity writes are applied to an in-memory
+// declarative configuration, seeded from the entities kong already has, and the whole configuration is then posted
+// to /config. Reads go straight to kong's entity endpoints.
+//
+// Terraform gives a provider no hook at the end of an apply, so writes are buffered and the configuration is posted
+// once no write came in for declarativeFlushDelay. Each write waits for that post and answers with its outcome, so the
+// writes terraform runs in parallel share one post and a change is in kong once its resource operation returns. When
+// kong rejects the configuration every write of the batch is rolled back and fails with kong's error.
+//
+// Kong only returns basic auth passwords and hashed oauth2 client secrets hashed, and hashes them again when they are
+// posted, so such credentials are refused rather than broken by the next post.
+type DeclarativeRoundTripper struct {
+	rt            http.RoundTripper
+	baseURL       string
+	formatVersion string
+
+	flushDelay    time.Duration
+	maxFlushDelay time.Duration
+
+	mu       sync.Mutex
+	seeded   bool
+	entities map[string][]map[string]interface{}
+	batch    *declarativeBatch
+}
+
+// declarativeBatch is the buffered writes waiting for the next post of the configuration.
+type declarativeBatch struct {
+	// snapshot is the configuration before the first write of the batch, restored when kong rejects it.
+	snapshot map[string][]map[string]interface{}
+	ctx      context.Context
+	header   http.Header
+	started  time.Time
+	timer    *time.Timer
+	done     chan struct{}
+
+	// the outcome of the post, set before done is closed.
+	status int
+	body   []byte
+	err    error
+}
+
+// NewDeclarativeRoundTripper returns a round tripper for the admin API at baseURL sending requests through rt.
+func NewDeclarativeRoundTripper(rt http.RoundTripper, baseURL string, formatVersion string) *DeclarativeRoundTripper {
+	if formatVersion == "" {
+		formatVersion = defaultDeclarativeFormatVersion
+	}
+	return &DeclarativeRoundTripper{
+		rt:            rt,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		formatVersion: formatVersion,
+		flushDelay:    declarativeFlushDelay,
+		maxFlushDelay: declarativeMaxFlushDelay,
+	}
+}
+
+// RoundTrip satisfies the RoundTripper interface.
+func (t *DeclarativeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.rt.RoundTrip(req)
+	}
+
+	write, ok := t.parseWrite(req)
+	if !ok {
+		return t.rt.RoundTrip(req)
+	}
+
+	answer, batch, err := t.buffer(req, write)
+	if err != nil || batch == nil {
+		return answer, err
+	}
+
+	select {
+	case <-batch.done:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	if batch.status >= 400 {
+		return rawJSONResponse(req, batch.status, batch.body), nil
+	}
+	return answer, nil
+}
+
+// buffer applies a write to the in-memory configuration and adds it to the batch waiting to be posted. A write that
+// is answered without a post returns no batch.
+func (t *DeclarativeRoundTripper) buffer(req *http.Request, write declarativeWrite) (*http.Response, *declarativeBatch, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.seeded {
+		if err := t.seed(req); err != nil {
+			return nil, nil, fmt.Errorf("could not read the current declarative configuration: %v", err)
+		}
+		if err := t.checkHashedSecrets(); err != nil {
+			return nil, nil, err
+		}
+		t.seeded = true
+	}
+
+	var body map[string]interface{}
+	if req.Body != nil {
+		raw, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(bytes.TrimSpace(raw)) > 0 {
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, nil, fmt.Errorf("could not decode %s %s body: %v", req.Method, req.URL.Path, err)
+			}
+		}
+	}
+
+	if kind := hashedSecretKind(write.collection, body); kind != "" && req.Method != http.MethodDelete {
+		resp, err := jsonResponse(req, http.StatusBadRequest, map[string]interface{}{
+			"message": fmt.Sprintf("%s can not be managed in db_less mode, kong only returns their secrets hashed and would hash them again when the configuration is posted", kind),
+		})
+		return resp, nil, err
+	}
+
+	snapshot := t.snapshot()
+	status, entity, err := t.apply(req.Method, write, body)
+	if err != nil {
+		t.entities = snapshot
+		return nil, nil, err
+	}
+	if status >= 400 {
+		resp, err := jsonResponse(req, status, map[string]interface{}{"message": http.StatusText(status)})
+		return resp, nil, err
+	}
+
+	// the answer is rendered now, later writes of the batch may change the entity
+	var answer *http.Response
+	if entity == nil {
+		answer, err = jsonResponse(req, http.StatusNoContent, nil)
+	} else {
+		answer, err = jsonResponse(req, status, entity)
+	}
+	if err != nil {
+		t.entities = snapshot
+		return nil, nil, err
+	}
+
+	if t.batch == nil {
+		t.batch = &declarativeBatch{
+			snapshot: snapshot,
+			ctx:      detachedContext{req.Context()},
+			header:   req.Header.Clone(),
+			started:  time.Now(),
+			done:     make(chan struct{}),
+		}
+		batch := t.batch
+		batch.timer = time.AfterFunc(t.flushDelay, func() { t.flush(batch) })
+	} else if wait := t.maxFlushDelay - time.Since(t.batch.started); wait < t.flushDelay {
+		t.batch.timer.Reset(wait)
+	} else {
+		t.batch.timer.Reset(t.flushDelay)
+	}
+
+	return answer, t.batch, nil
+}
+
+// flush posts the configuration with the writes of the batch, and rolls them back when kong rejects it.
+func (t *DeclarativeRoundTripper) flush(batch *declarativeBatch) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.batch != batch {
+		return
+	}
+	t.batch = nil
+	defer close(batch.done)
+
+	resp, err := t.post(batch.ctx, batch.header)
+	if err != nil {
+		t.entities = batch.snapshot
+		batch.err = err
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		t.entities = batch.snapshot
+		batch.status = resp.StatusCode
+		batch.body, batch.err = ioutil.ReadAll(resp.Body)
+	}
+}
+
+// declarativeWrite is an entity write parsed from an admin API path.
+type declarativeWrite struct {
+	collection string
+	id         string
+	parent     string
+	parentID   string
+}
+
+func (t *DeclarativeRoundTripper) parseWrite(req *http.Request) (declarativeWrite, bool) {
+	path := req.URL.Path
+	if base := strings.Index(t.baseURL, "://"); base >= 0 {
+		if slash := strings.Index(t.baseURL[base+3:], "/"); slash >= 0 {
+			path = strings.TrimPrefix(path, t.baseURL[base+3+slash:])
+		}
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var write declarativeWrite
+	switch len(segments) {
+	case 1, 2:
+		write.collection = segments[0]
+		if len(segments) == 2 {
+			write.id = segments[1]
+		}
+	case 3, 4:
+		if _, ok := declarativeParents[segments[0]]; !ok {
+			return write, false
+		}
+		write.parent, write.parentID, write.collection = segments[0], segments[1], segments[2]
+		if len(segments) == 4 {
+			write.id = segments[3]
+		}
+	default:
+		return write, false
+	}
+
+	if _, ok := declarativeEntities[write.collection]; !ok {
+		return write, false
+	}
+	if write.id == "" && req.Method != http.MethodPost {
+		return write, false
+	}
+	return write, true
+}
+
+// apply makes the write to the in-memory configuration and returns the status and entity to answer with.
+func (t *DeclarativeRoundTripper) apply(method string, write declarativeWrite, body map[string]interface{}) (int, map[string]interface{}, error) {
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	if write.parent != "" {
+		parentID := write.parentID
+		if parent := t.find(write.parent, parentID); parent != nil {
+			parentID = parent["id"].(string)
+		} else if method != http.MethodDelete {
+			return http.StatusNotFound, nil, nil
+		}
+		body[declarativeParents[write.parent]] = map[string]interface{}{"id": parentID}
+	}
+
+	existing := t.find(write.collection, write.id)
+
+	switch method {
+	case http.MethodPost:
+		if id, ok := body["id"].(string); ok && t.find(write.collection, id) != nil {
+			return http.StatusConflict, nil, nil
+		}
+		return http.StatusCreated, t.insert(write.collection, body), nil
+	case http.MethodPut:
+		if existing != nil {
+			body["id"] = existing["id"]
+			body["created_at"] = existing["created_at"]
+			t.remove(write.collection, existing["id"].(string), false)
+		} else if uuidPattern.MatchString(write.id) {
+			body["id"] = write.id
+		} else if field := declarativeEntities[write.collection].nameField; field != "" {
+			body[field] = write.id
+		}
+		return http.StatusOK, t.insert(write.collection, body), nil
+	case http.MethodPatch:
+		if existing == nil {
+			return http.StatusNotFound, nil, nil
+		}
+		mergeDeclarativeEntity(existing, body)
+		existing["updated_at"] = time.Now().Unix()
+		return http.StatusOK, existing, nil
+	case http.MethodDelete:
+		if existing != nil {
+			t.remove(write.collection, existing["id"].(string), true)
+		}
+		return http.StatusNoContent, nil, nil
+	}
+
+	return http.StatusMethodNotAllowed, nil, nil
+}
+
+func (t *DeclarativeRoundTripper) insert(collection string, entity map[string]interface{}) map[string]interface{} {
+	if id, ok := entity["id"].(string); !ok || id == "" {
+		entity["id"] = newUUID()
+	}
+	if _, ok := entity["created_at"].(float64); !ok {
+		entity["created_at"] = time.Now().Unix()
+	}
+	t.entities[collection] = append(t.entities[collection], entity)
+	return entity
+}
+
+func (t *DeclarativeRoundTripper) find(collection string, idOrName string) map[string]interface{} {
+	nameField := declarativeEntities[collection].nameField
+	for _, entity := range t.entities[collection] {
+		if entity["id"] == idOrName || (nameField != "" && entity[nameField] == idOrName) {
+			return entity
+		}
+	}
+	return nil
+}
+
+// remove deletes an entity, with cascade the entities referring to it are deleted as kong would.
+func (t *DeclarativeRoundTripper) remove(collection string, id string, cascade bool) {
+	var kept []map[string]interface{}
+	for _, entity := range t.entities[collection] {
+		if entity["id"] != id {
+			kept = append(kept, entity)
+		}
+	}
+	t.entities[collection] = kept
+
+	if !cascade {
+		return
+	}
+	for other, entities := range t.entities {
+		for _, entity := range entities {
+			if referencesDeclarativeEntity(entity, id) {
+				t.remove(other, entity["id"].(string), true)
+			}
+		}
+	}
+}
+
+func referencesDeclarativeEntity(entity map[string]interface{}, id string) bool {
+	for _, value := range entity {
+		if ref, ok := value.(map[string]interface{}); ok && len(ref) == 1 && ref["id"] == id {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeDeclarativeEntity(existing map[string]interface{}, patch map[string]interface{}) {
+	for key, value := range patch {
+		current, currentIsMap := existing[key].(map[string]interface{})
+		update, updateIsMap := value.(map[string]interface{})
+		if currentIsMap && updateIsMap && key != "id" {
+			mergeDeclarativeEntity(current, update)
+		} else {
+			existing[key] = value
+		}
+	}
+}
+
+// render builds the declarative configuration, foreign keys are written as plain ids.
+func (t *DeclarativeRoundTripper) render() map[string]interface{} {
+	config := map[string]interface{}{"_format_version": t.formatVersion}
+
+	for collection, entities := range t.entities {
+		if len(entities) == 0 {
+			continue
+		}
+		var rendered []map[string]interface{}
+		for _, entity := range entities {
+			out := map[string]interface{}{}
+			for key, value := range entity {
+				if contains(declarativeOmittedFields, key) || value == nil {
+					continue
+				}
+				if ref, ok := value.(map[string]interface{}); ok && len(ref) == 1 && ref["id"] != nil {
+					value = ref["id"]
+				}
+				out[key] = value
+			}
+			rendered = append(rendered, out)
+		}
+		config[declarativeEntities[collection].key] = rendered
+	}
+
+	return config
+}
+
+func (t *DeclarativeRoundTripper) post(ctx context.Context, header http.Header) (*http.Response, error) {
+	declarative, err := json.Marshal(t.render())
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]string{"config": string(declarative)})
+	if err != nil {
+		return nil, err
+	}
+
+	configReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/config", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	configReq.Header = header.Clone()
+	configReq.Header.Set("Content-Type", "application/json")
+
+	return t.rt.RoundTrip(configReq)
+}
+
+// seed loads the entities kong already has so that posting the configuration keeps them.
+func (t *DeclarativeRoundTripper) seed(req *http.Request) error {
+	t.entities = map[string][]map[string]interface{}{}
+
+	for collection, entity := range declarativeEntities {
+		if entity.listPath == "" {
+			continue
+		}
+		entities, err := t.list(req, entity.listPath)
+		if err != nil {
+			return err
+		}
+		t.entities[collection] = entities
+	}
+
+	for _, upstream := range t.entities["upstreams"] {
+		targets, err := t.list(req, fmt.Sprintf("/upstreams/%s/targets", upstream["id"]))
+		if err != nil {
+			return err
+		}
+		t.entities["targets"] = append(t.entities["targets"], targets...)
+	}
+
+	return nil
+}
+
+// checkHashedSecrets fails when kong has credentials whose secrets posting the configuration would break.
+func (t *DeclarativeRoundTripper) checkHashedSecrets() error {
+	for _, collection := range []string{"basic-auth", "oauth2"} {
+		for _, entity := range t.entities[collection] {
+			if kind := hashedSecretKind(collection, entity); kind != "" {
+				return fmt.Errorf("kong has %s, db_less mode can not post a configuration keeping them as kong only returns their secrets hashed", kind)
+			}
+		}
+	}
+	return nil
+}
+
+// hashedSecretKind names the credentials of an entity whose secret kong only returns hashed, or is empty.
+func hashedSecretKind(collection string, entity map[string]interface{}) string {
+	switch {
+	case collection == "basic-auth":
+		return "basic auth credentials"
+	case collection == "oauth2" && entity["hash_secret"] == true:
+		return "oauth2 credentials with hash_secret"
+	}
+	return ""
+}
+
+// list pages through a collection, a collection kong does not know of is empty.
+func (t *DeclarativeRoundTripper) list(req *http.Request, path string) ([]map[string]interface{}, error) {
+	var entities []map[string]interface{}
+
+	next := t.baseURL + path + "?size=1000"
+	for next != "" {
+		listReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		listReq.Header = req.Header.Clone()
+		listReq.Header.Del("Content-Type")
+
+		resp, err := t.rt.RoundTrip(listReq)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Data []map[string]interface{} `json:"data"`
+			Next *string                  `json:"next"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("listing %s returned %s", path, resp.Status)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, page.Data...)
+		next = ""
+		if page.Next != nil && *page.Next != "" {
+			next = t.resolveNext(*page.Next)
+		}
+	}
+
+	return entities, nil
+}
+
+// resolveNext turns the next page kong returns, a path below the admin API root, into a url.
+func (t *DeclarativeRoundTripper) resolveNext(next string) string {
+	if strings.Contains(next, "://") {
+		return next
+	}
+	root := t.baseURL
+	if base := strings.Index(root, "://"); base >= 0 {
+		if slash := strings.Index(root[base+3:], "/"); slash >= 0 {
+			root = root[:base+3+slash]
+		}
+	}
+	return root + next
+}
+
+func (t *DeclarativeRoundTripper) snapshot() map[string][]map[string]interface{} {
+	raw, _ := json.Marshal(t.entities)
+	snapshot := map[string][]map[string]interface{}{}
+	_ = json.Unmarshal(raw, &snapshot)
+	return snapshot
+}
+
+func jsonResponse(req *http.Request, status int, body interface{}) (*http.Response, error) {
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	return rawJSONResponse(req, status, raw), nil
+}
+
+// detachedContext keeps the values of the context of the write that started a batch, such as its logger, without its
+// cancellation. The post is shared by every write of the batch, it is not canceled with any one of them.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// rawJSONResponse answers with a body kong returned, so each write of a rejected batch gets kong's error.
+func rawJSONResponse(req *http.Request, status int, raw []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(raw)),
+		ContentLength: int64(len(raw)),
+		Request:       req,
+	}
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
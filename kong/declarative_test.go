@@ -0,0 +1,239 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// dbLessKong is a stand-in for a DB-less kong, it lists the seeded entities and records the configurations posted to
+// /config.
+type dbLessKong struct {
+	seed    map[string][]map[string]interface{}
+	configs []map[string]interface{}
+	reject  bool
+}
+
+func (k *dbLessKong) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodPost && r.URL.Path == "/config" {
+		var body struct {
+			Config string `json:"config"`
+		}
+		var config map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || json.Unmarshal([]byte(body.Config), &config) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if k.reject {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"declarative config is invalid"}`))
+			return
+		}
+		k.configs = append(k.configs, config)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_, _ = w.Write([]byte(`{"message":"cannot create or update entities in DB-less mode"}`))
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": k.seed[r.URL.Path], "next": nil})
+}
+
+func (k *dbLessKong) lastConfig(t *testing.T) map[string]interface{} {
+	if len(k.configs) == 0 {
+		t.Fatal("expected a configuration to be posted to /config")
+	}
+	return k.configs[len(k.configs)-1]
+}
+
+func newDBLessClient(t *testing.T, fake *dbLessKong) *kong.Client {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := GetKongClient(Config{Address: server.URL, DBLess: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestDeclarativeCreateAndCascadingDelete(t *testing.T) {
+	fake := &dbLessKong{}
+	client := newDBLessClient(t, fake)
+	ctx := context.Background()
+
+	service, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("svc"), Host: kong.String("example.com")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.ID == nil || !uuidPattern.MatchString(*service.ID) {
+		t.Fatalf("expected the service to get a generated id, got %v", service.ID)
+	}
+
+	route, err := client.Routes.Create(ctx, &kong.Route{Name: kong.String("route"), Paths: kong.StringSlice("/"), Service: &kong.Service{ID: service.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := fake.lastConfig(t)
+	if config["_format_version"] != defaultDeclarativeFormatVersion {
+		t.Errorf("expected _format_version %s got %v", defaultDeclarativeFormatVersion, config["_format_version"])
+	}
+	routes, _ := config["routes"].([]interface{})
+	if len(routes) != 1 || routes[0].(map[string]interface{})["service"] != *service.ID {
+		t.Errorf("expected route %s to refer to service %s by id, got %v", *route.ID, *service.ID, config["routes"])
+	}
+
+	updated, err := client.Services.Update(ctx, &kong.Service{ID: service.ID, Port: kong.Int(8080)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updated.Port != 8080 || *updated.Name != "svc" {
+		t.Errorf("expected the update to be merged into the service, got %v", updated)
+	}
+
+	if err := client.Services.Delete(ctx, service.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	config = fake.lastConfig(t)
+	if config["services"] != nil || config["routes"] != nil {
+		t.Errorf("expected deleting the service to delete its route, got %v", config)
+	}
+}
+
+func TestDeclarativeSeedsExistingEntities(t *testing.T) {
+	fake := &dbLessKong{
+		seed: map[string][]map[string]interface{}{
+			"/consumers": {{"id": "b7b2b2d2-7d0e-4bd0-8a2b-7f6f0e7b5a11", "username": "alice", "created_at": float64(1)}},
+		},
+	}
+	client := newDBLessClient(t, fake)
+
+	keyAuth, err := client.KeyAuths.Create(context.Background(), kong.String("alice"), &kong.KeyAuth{Key: kong.String("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyAuth.Consumer == nil || *keyAuth.Consumer.ID != "b7b2b2d2-7d0e-4bd0-8a2b-7f6f0e7b5a11" {
+		t.Errorf("expected the credential to refer to the consumer by id, got %v", keyAuth.Consumer)
+	}
+
+	config := fake.lastConfig(t)
+	consumers, _ := config["consumers"].([]interface{})
+	if len(consumers) != 1 {
+		t.Errorf("expected the existing consumer to be kept, got %v", config["consumers"])
+	}
+	credentials, _ := config["keyauth_credentials"].([]interface{})
+	if len(credentials) != 1 || credentials[0].(map[string]interface{})["consumer"] != "b7b2b2d2-7d0e-4bd0-8a2b-7f6f0e7b5a11" {
+		t.Errorf("expected one keyauth_credentials entry for the consumer, got %v", config["keyauth_credentials"])
+	}
+}
+
+func TestDeclarativeRollsBackRejectedConfig(t *testing.T) {
+	fake := &dbLessKong{}
+	client := newDBLessClient(t, fake)
+	ctx := context.Background()
+
+	fake.reject = true
+	_, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("rejected")})
+	if err == nil {
+		t.Fatal("expected kong rejecting the configuration to fail the create")
+	}
+
+	fake.reject = false
+	if _, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("accepted")}); err != nil {
+		t.Fatal(err)
+	}
+
+	consumers, _ := fake.lastConfig(t)["consumers"].([]interface{})
+	if len(consumers) != 1 || consumers[0].(map[string]interface{})["username"] != "accepted" {
+		t.Errorf("expected only the accepted consumer in the configuration, got %v", consumers)
+	}
+}
+
+func TestDeclarativeBatchesParallelWrites(t *testing.T) {
+	fake := &dbLessKong{}
+	client := newDBLessClient(t, fake)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for _, username := range []string{"a", "b", "c", "d", "e"} {
+		wg.Add(1)
+		go func(username string) {
+			defer wg.Done()
+			_, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String(username)})
+			errs <- err
+		}(username)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(fake.configs) != 1 {
+		t.Errorf("expected the parallel writes to share one post, got %d", len(fake.configs))
+	}
+	if consumers, _ := fake.lastConfig(t)["consumers"].([]interface{}); len(consumers) != 5 {
+		t.Errorf("expected every consumer in the configuration, got %v", consumers)
+	}
+}
+
+func TestDeclarativeRefusesHashedSecrets(t *testing.T) {
+	fake := &dbLessKong{}
+	client := newDBLessClient(t, fake)
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("alice")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := len(fake.configs)
+
+	if _, err := client.BasicAuths.Create(ctx, consumer.ID, &kong.BasicAuth{Username: kong.String("alice"), Password: kong.String("secret")}); err == nil || !strings.Contains(err.Error(), "basic auth credentials can not be managed in db_less mode") {
+		t.Errorf("expected creating a basic auth credential to be refused, got %v", err)
+	}
+	if _, err := client.Oauth2Credentials.Create(ctx, consumer.ID, &kong.Oauth2Credential{Name: kong.String("app"), HashSecret: kong.Bool(true)}); err == nil || !strings.Contains(err.Error(), "oauth2 credentials with hash_secret can not be managed") {
+		t.Errorf("expected creating an oauth2 credential with hash_secret to be refused, got %v", err)
+	}
+	if len(fake.configs) != posted {
+		t.Errorf("expected no configuration to be posted for refused credentials, got %d more", len(fake.configs)-posted)
+	}
+
+	if _, err := client.Oauth2Credentials.Create(ctx, consumer.ID, &kong.Oauth2Credential{Name: kong.String("app"), ClientSecret: kong.String("plain")}); err != nil {
+		t.Errorf("expected an oauth2 credential keeping its secret in the clear to be created, got %v", err)
+	}
+}
+
+func TestDeclarativeRefusesKongWithHashedSecrets(t *testing.T) {
+	fake := &dbLessKong{
+		seed: map[string][]map[string]interface{}{
+			"/consumers":   {{"id": "b7b2b2d2-7d0e-4bd0-8a2b-7f6f0e7b5a11", "username": "alice"}},
+			"/basic-auths": {{"id": "0c3f2b0e-1d6a-4f3b-9a7e-2b1c0d9e8f71", "username": "alice", "password": "0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c", "consumer": map[string]interface{}{"id": "b7b2b2d2-7d0e-4bd0-8a2b-7f6f0e7b5a11"}}},
+		},
+	}
+	client := newDBLessClient(t, fake)
+
+	if _, err := client.Services.Create(context.Background(), &kong.Service{Name: kong.String("svc"), Host: kong.String("example.com")}); err == nil || !strings.Contains(err.Error(), "kong has basic auth credentials") {
+		t.Errorf("expected writing to a kong with basic auth credentials to be refused, got %v", err)
+	}
+	if len(fake.configs) != 0 {
+		t.Errorf("expected no configuration to be posted, got %v", fake.configs)
+	}
+}
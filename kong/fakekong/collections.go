@@ -0,0 +1,319 @@
+package fakekong
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+)
+
+// foreignKey is a field of an entity referring to an entity of another collection.
+type foreignKey struct {
+	field      string
+	collection string
+	// cascade deletes the entity with the one it refers to, otherwise the referred entity can not be deleted.
+	cascade bool
+}
+
+// collection describes how kong stores, validates and addresses the entities of one admin api collection.
+type collection struct {
+	name string
+	// endpointKey is the unique field an entity can be addressed by instead of its id.
+	endpointKey string
+	foreignKeys []foreignKey
+	required    []string
+	// unique are the fields, or combinations of fields, no two entities may share.
+	unique [][]string
+	// defaults returns the values kong fills in for fields that are not set.
+	defaults func() map[string]interface{}
+	// prepare generates and hashes fields before the entity is stored.
+	prepare func(entity map[string]interface{})
+	// validate returns the schema violations of an entity by field.
+	validate func(entity map[string]interface{}) map[string]string
+}
+
+var collections = map[string]*collection{
+	"services": {
+		name:        "services",
+		endpointKey: "name",
+		foreignKeys: []foreignKey{{field: "client_certificate", collection: "certificates"}},
+		required:    []string{"host"},
+		unique:      [][]string{{"name"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{
+				"protocol":        "http",
+				"port":            80,
+				"retries":         5,
+				"connect_timeout": 60000,
+				"write_timeout":   60000,
+				"read_timeout":    60000,
+			}
+		},
+	},
+	"routes": {
+		name:        "routes",
+		endpointKey: "name",
+		foreignKeys: []foreignKey{{field: "service", collection: "services"}},
+		unique:      [][]string{{"name"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{
+				"protocols":                  []interface{}{"http", "https"},
+				"regex_priority":             0,
+				"strip_path":                 true,
+				"preserve_host":              false,
+				"https_redirect_status_code": 426,
+				"path_handling":              "v0",
+				"request_buffering":          true,
+				"response_buffering":         true,
+			}
+		},
+		validate: validateRoute,
+	},
+	"consumers": {
+		name:        "consumers",
+		endpointKey: "username",
+		unique:      [][]string{{"username"}, {"custom_id"}},
+		validate: func(entity map[string]interface{}) map[string]string {
+			if entity["username"] == nil && entity["custom_id"] == nil {
+				return map[string]string{"@entity": "at least one of these fields must be non-empty: 'custom_id', 'username'"}
+			}
+			return nil
+		},
+	},
+	"plugins": {
+		name: "plugins",
+		foreignKeys: []foreignKey{
+			{field: "service", collection: "services", cascade: true},
+			{field: "route", collection: "routes", cascade: true},
+			{field: "consumer", collection: "consumers", cascade: true},
+		},
+		required: []string{"name"},
+		unique:   [][]string{{"name", "service", "route", "consumer"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{
+				"enabled":   true,
+				"protocols": []interface{}{"grpc", "grpcs", "http", "https"},
+				"config":    map[string]interface{}{},
+			}
+		},
+	},
+	"upstreams": {
+		name:        "upstreams",
+		endpointKey: "name",
+		foreignKeys: []foreignKey{{field: "client_certificate", collection: "certificates"}},
+		required:    []string{"name"},
+		unique:      [][]string{{"name"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{
+				"algorithm":           "round-robin",
+				"slots":               10000,
+				"hash_on":             "none",
+				"hash_fallback":       "none",
+				"hash_on_cookie_path": "/",
+				"healthchecks":        defaultHealthchecks(),
+			}
+		},
+	},
+	"targets": {
+		name:        "targets",
+		endpointKey: "target",
+		foreignKeys: []foreignKey{{field: "upstream", collection: "upstreams", cascade: true}},
+		required:    []string{"target"},
+		unique:      [][]string{{"upstream", "target"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{"weight": 100}
+		},
+	},
+	"certificates": {
+		name:     "certificates",
+		required: []string{"cert", "key"},
+	},
+	"ca_certificates": {
+		name:     "ca_certificates",
+		required: []string{"cert"},
+	},
+	"keyauth_credentials": {
+		name:        "keyauth_credentials",
+		endpointKey: "key",
+		foreignKeys: []foreignKey{{field: "consumer", collection: "consumers", cascade: true}},
+		unique:      [][]string{{"key"}},
+		prepare: func(entity map[string]interface{}) {
+			if entity["key"] == nil {
+				entity["key"] = randomHex(16)
+			}
+		},
+	},
+	"basicauth_credentials": {
+		name:        "basicauth_credentials",
+		endpointKey: "username",
+		foreignKeys: []foreignKey{{field: "consumer", collection: "consumers", cascade: true}},
+		required:    []string{"username", "password"},
+		unique:      [][]string{{"username"}},
+		prepare: func(entity map[string]interface{}) {
+			// kong stores the salted sha1 of the password and only ever returns the hash.
+			if password, ok := entity["password"].(string); ok && entity["_password"] != password {
+				consumer, _ := entity["consumer"].(map[string]interface{})
+				consumerID, _ := consumer["id"].(string)
+				hash := sha1.Sum([]byte(password + consumerID))
+				entity["password"] = hex.EncodeToString(hash[:])
+				entity["_password"] = entity["password"]
+			}
+		},
+	},
+	"acls": {
+		name:        "acls",
+		endpointKey: "group",
+		foreignKeys: []foreignKey{{field: "consumer", collection: "consumers", cascade: true}},
+		required:    []string{"group"},
+		unique:      [][]string{{"consumer", "group"}},
+	},
+	"jwt_secrets": {
+		name:        "jwt_secrets",
+		endpointKey: "key",
+		foreignKeys: []foreignKey{{field: "consumer", collection: "consumers", cascade: true}},
+		unique:      [][]string{{"key"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{"algorithm": "HS256"}
+		},
+		prepare: func(entity map[string]interface{}) {
+			if entity["key"] == nil {
+				entity["key"] = randomHex(16)
+			}
+			if entity["secret"] == nil {
+				entity["secret"] = randomHex(16)
+			}
+		},
+	},
+	"oauth2_credentials": {
+		name:        "oauth2_credentials",
+		endpointKey: "client_id",
+		foreignKeys: []foreignKey{{field: "consumer", collection: "consumers", cascade: true}},
+		required:    []string{"name"},
+		unique:      [][]string{{"client_id"}},
+		defaults: func() map[string]interface{} {
+			return map[string]interface{}{"hash_secret": false}
+		},
+		prepare: func(entity map[string]interface{}) {
+			if entity["client_id"] == nil {
+				entity["client_id"] = randomHex(16)
+			}
+			if entity["client_secret"] == nil {
+				entity["client_secret"] = randomHex(16)
+			}
+		},
+	},
+	"hmacauth_credentials": {
+		name:        "hmacauth_credentials",
+		endpointKey: "username",
+		foreignKeys: []foreignKey{{field: "consumer", collection: "consumers", cascade: true}},
+		required:    []string{"username"},
+		unique:      [][]string{{"username"}},
+		prepare: func(entity map[string]interface{}) {
+			if entity["secret"] == nil {
+				entity["secret"] = randomHex(16)
+			}
+		},
+	},
+}
+
+// topLevelPaths maps the top level admin api paths onto their collection.
+var topLevelPaths = map[string]string{
+	"services":        "services",
+	"routes":          "routes",
+	"consumers":       "consumers",
+	"plugins":         "plugins",
+	"upstreams":       "upstreams",
+	"certificates":    "certificates",
+	"ca_certificates": "ca_certificates",
+	"key-auths":       "keyauth_credentials",
+	"basic-auths":     "basicauth_credentials",
+	"acls":            "acls",
+	"jwts":            "jwt_secrets",
+	"oauth2":          "oauth2_credentials",
+	"hmac-auths":      "hmacauth_credentials",
+}
+
+// nestedPaths maps the paths nested below an entity, such as /consumers/{consumer}/key-auth, onto their collection
+// keyed by the parent collection.
+var nestedPaths = map[string]map[string]string{
+	"services": {
+		"routes":  "routes",
+		"plugins": "plugins",
+	},
+	"routes": {
+		"plugins": "plugins",
+	},
+	"consumers": {
+		"plugins":    "plugins",
+		"key-auth":   "keyauth_credentials",
+		"basic-auth": "basicauth_credentials",
+		"acls":       "acls",
+		"jwt":        "jwt_secrets",
+		"oauth2":     "oauth2_credentials",
+		"hmac-auth":  "hmacauth_credentials",
+	},
+	"upstreams": {
+		"targets": "targets",
+	},
+}
+
+func (c *collection) foreignKey(collectionName string) *foreignKey {
+	for i := range c.foreignKeys {
+		if c.foreignKeys[i].collection == collectionName {
+			return &c.foreignKeys[i]
+		}
+	}
+	return nil
+}
+
+func validateRoute(entity map[string]interface{}) map[string]string {
+	protocols, _ := entity["protocols"].([]interface{})
+	for _, protocol := range protocols {
+		if protocol != "http" && protocol != "https" {
+			continue
+		}
+		for _, field := range []string{"methods", "hosts", "headers", "paths", "snis"} {
+			if isSet(entity[field]) {
+				return nil
+			}
+		}
+		return map[string]string{"@entity": "must set one of 'methods', 'hosts', 'headers', 'paths' when 'protocols' is 'http'"}
+	}
+	return nil
+}
+
+func defaultHealthchecks() map[string]interface{} {
+	return map[string]interface{}{
+		"threshold": 0,
+		"active": map[string]interface{}{
+			"type":                     "http",
+			"timeout":                  1,
+			"concurrency":              10,
+			"http_path":                "/",
+			"https_verify_certificate": true,
+			"healthy": map[string]interface{}{
+				"interval":      0,
+				"successes":     0,
+				"http_statuses": []interface{}{200, 302},
+			},
+			"unhealthy": map[string]interface{}{
+				"interval":      0,
+				"tcp_failures":  0,
+				"timeouts":      0,
+				"http_failures": 0,
+				"http_statuses": []interface{}{429, 404, 500, 501, 502, 503, 504, 505},
+			},
+		},
+		"passive": map[string]interface{}{
+			"type": "http",
+			"healthy": map[string]interface{}{
+				"successes":     0,
+				"http_statuses": []interface{}{200, 201, 202, 203, 204, 205, 206, 207, 208, 226, 300, 301, 302, 303, 304, 305, 306, 307, 308},
+			},
+			"unhealthy": map[string]interface{}{
+				"tcp_failures":  0,
+				"timeouts":      0,
+				"http_failures": 0,
+				"http_statuses": []interface{}{429, 500, 503},
+			},
+		},
+	}
+}
@@ -0,0 +1,842 @@
+// Package fakekong serves an in-memory fake of the kong admin api through httptest. It keeps the entities of the
+// collections the provider manages and mimics the defaults, pagination, tag filtering and errors of a kong backed by
+// a database closely enough to run the resource tests without docker.
+package fakekong
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Server is a running fake of the admin api, its URL is the admin address to point a client at.
+type Server struct {
+	*httptest.Server
+
+	version string
+
+	mu sync.Mutex
+	// entities are kept per collection in the order they were created in, which is the order they are listed in.
+	entities map[string][]map[string]interface{}
+	// health is the health set on targets through the healthy and unhealthy endpoints keyed by target id.
+	health map[string]string
+	now    func() time.Time
+}
+
+// NewServer starts a fake admin api reporting the given kong version. Close the server when done with it.
+func NewServer(version string) *Server {
+	s := &Server{
+		version:  version,
+		entities: map[string][]map[string]interface{}{},
+		health:   map[string]string{},
+		now:      time.Now,
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// apiError is an error response of the admin api.
+type apiError struct {
+	status int
+	body   map[string]interface{}
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprint(e.body["message"])
+}
+
+func notFound() *apiError {
+	return &apiError{status: http.StatusNotFound, body: map[string]interface{}{"message": "Not found"}}
+}
+
+func badRequest(message string) *apiError {
+	return &apiError{status: http.StatusBadRequest, body: map[string]interface{}{"message": message}}
+}
+
+func schemaViolation(fields map[string]string) *apiError {
+	var messages []string
+	for _, field := range sortedKeys(fields) {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, fields[field]))
+	}
+	return &apiError{status: http.StatusBadRequest, body: map[string]interface{}{
+		"code":    2,
+		"name":    "schema violation",
+		"message": fmt.Sprintf("schema violation (%s)", strings.Join(messages, "; ")),
+		"fields":  fields,
+	}}
+}
+
+func uniqueViolation(fields map[string]interface{}) *apiError {
+	var values []string
+	for _, field := range sortedKeys(fields) {
+		values = append(values, fmt.Sprintf("%s=%s", field, describe(fields[field])))
+	}
+	return &apiError{status: http.StatusConflict, body: map[string]interface{}{
+		"code":    5,
+		"name":    "unique constraint violation",
+		"message": fmt.Sprintf("UNIQUE violation detected on '{%s}'", strings.Join(values, ",")),
+		"fields":  fields,
+	}}
+}
+
+func primaryKeyViolation(id string) *apiError {
+	return &apiError{status: http.StatusConflict, body: map[string]interface{}{
+		"code":    3,
+		"name":    "primary key violation",
+		"message": fmt.Sprintf("primary key violation on key '{id=\"%s\"}'", id),
+		"fields":  map[string]interface{}{"id": id},
+	}}
+}
+
+func foreignKeyViolation(field string, id string, collection string) *apiError {
+	return &apiError{status: http.StatusBadRequest, body: map[string]interface{}{
+		"code":    4,
+		"name":    "foreign key violation",
+		"message": fmt.Sprintf("the foreign key '{id=\"%s\"}' does not reference an existing '%s' entity.", id, collection),
+		"fields":  map[string]interface{}{field: map[string]interface{}{"id": id}},
+	}}
+}
+
+func referencedViolation(collection string, referrer string) *apiError {
+	return &apiError{status: http.StatusBadRequest, body: map[string]interface{}{
+		"code":    4,
+		"name":    "foreign key violation",
+		"message": fmt.Sprintf("an existing '%s' entity references this '%s' entity", referrer, collection),
+		"fields":  map[string]interface{}{"@referenced_by": referrer},
+	}}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, body, err := s.handle(r)
+	if apiErr, ok := err.(*apiError); ok {
+		status, body = apiErr.status, apiErr.body
+	} else if err != nil {
+		status, body = http.StatusBadRequest, map[string]interface{}{"message": err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Kong-Admin-Request-ID", newUUID())
+	w.WriteHeader(status)
+	if status != http.StatusNoContent {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func (s *Server) handle(r *http.Request) (int, interface{}, error) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.URL.Path == "/" && r.Method == http.MethodGet:
+		return http.StatusOK, s.info(), nil
+	case r.URL.Path == "/status" && r.Method == http.MethodGet:
+		return http.StatusOK, map[string]interface{}{
+			"database": map[string]interface{}{"reachable": true},
+			"server":   map[string]interface{}{"connections_active": 1},
+		}, nil
+	case len(segments) == 3 && segments[0] == "upstreams" && segments[2] == "health" && r.Method == http.MethodGet:
+		return s.upstreamHealth(segments[1])
+	case len(segments) == 5 && segments[0] == "upstreams" && segments[2] == "targets" &&
+		(segments[4] == "healthy" || segments[4] == "unhealthy") && r.Method == http.MethodPost:
+		return s.setTargetHealth(segments[1], segments[3], segments[4])
+	}
+
+	name, ok := topLevelPaths[segments[0]]
+	if !ok {
+		return 0, nil, notFound()
+	}
+	c := collections[name]
+
+	var parentKey *foreignKey
+	var parent map[string]interface{}
+	if len(segments) >= 3 {
+		nested, ok := nestedPaths[name][segments[2]]
+		if !ok {
+			return 0, nil, notFound()
+		}
+		if parent = s.find(name, segments[1], nil); parent == nil {
+			return 0, nil, notFound()
+		}
+		parentKey = collections[nested].foreignKey(name)
+		c = collections[nested]
+		segments = append([]string{segments[2]}, segments[3:]...)
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		return s.list(c, r, parentKey, parent)
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		body, err := decodeBody(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		return s.create(c, body, parentKey, parent)
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		entity := s.find(c.name, segments[1], scope(parentKey, parent))
+		if entity == nil {
+			return 0, nil, notFound()
+		}
+		return http.StatusOK, s.render(entity), nil
+	case len(segments) == 2 && r.Method == http.MethodPut:
+		body, err := decodeBody(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		return s.upsert(c, segments[1], body, parentKey, parent)
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		body, err := decodeBody(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		return s.update(c, segments[1], body, parentKey, parent)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		return s.delete(c, segments[1], parentKey, parent)
+	case len(segments) <= 2:
+		return 0, nil, &apiError{status: http.StatusMethodNotAllowed, body: map[string]interface{}{"message": "Method not allowed"}}
+	}
+	return 0, nil, notFound()
+}
+
+func (s *Server) info() map[string]interface{} {
+	available := map[string]interface{}{}
+	for _, plugin := range []string{
+		"acl", "basic-auth", "cors", "file-log", "hmac-auth", "ip-restriction", "jwt", "key-auth", "oauth2",
+		"rate-limiting", "request-termination", "request-transformer", "response-transformer",
+	} {
+		available[plugin] = true
+	}
+	return map[string]interface{}{
+		"version":  s.version,
+		"tagline":  "Welcome to kong",
+		"hostname": "fakekong",
+		"configuration": map[string]interface{}{
+			"database": "postgres",
+		},
+		"plugins": map[string]interface{}{
+			"available_on_server": available,
+		},
+	}
+}
+
+// scope returns the foreign key restricting lookups to the entities nested below a parent, or nil for top level paths.
+func scope(key *foreignKey, parent map[string]interface{}) map[string]interface{} {
+	if key == nil {
+		return nil
+	}
+	return map[string]interface{}{key.field: parent["id"]}
+}
+
+// find looks up an entity by its id or endpoint key, restricted to the entities whose foreign keys match within.
+func (s *Server) find(collectionName string, idOrKey string, within map[string]interface{}) map[string]interface{} {
+	c := collections[collectionName]
+	for _, entity := range s.live(collectionName) {
+		if entity["id"] != idOrKey && (c.endpointKey == "" || entity[c.endpointKey] != idOrKey) {
+			continue
+		}
+		if matchesScope(entity, within) {
+			return entity
+		}
+	}
+	return nil
+}
+
+func matchesScope(entity map[string]interface{}, within map[string]interface{}) bool {
+	for field, id := range within {
+		if foreignID(entity[field]) != id {
+			return false
+		}
+	}
+	return true
+}
+
+// live returns the entities of a collection, dropping key-auth credentials whose ttl has run out.
+func (s *Server) live(collectionName string) []map[string]interface{} {
+	var entities []map[string]interface{}
+	for _, entity := range s.entities[collectionName] {
+		if expires, ok := entity["_expires_at"].(time.Time); ok && !s.now().Before(expires) {
+			continue
+		}
+		entities = append(entities, entity)
+	}
+	s.entities[collectionName] = entities
+	return entities
+}
+
+func (s *Server) list(c *collection, r *http.Request, parentKey *foreignKey, parent map[string]interface{}) (int, interface{}, error) {
+	query := r.URL.Query()
+
+	size := defaultPageSize
+	if raw := query.Get("size"); raw != "" {
+		var err error
+		if size, err = strconv.Atoi(raw); err != nil || size < 1 || size > maxPageSize {
+			return 0, nil, schemaViolation(map[string]string{"size": fmt.Sprintf("size must be an integer between 1 and %d", maxPageSize)})
+		}
+	}
+
+	start := 0
+	if raw := query.Get("offset"); raw != "" {
+		decoded, err := base64.StdEncoding.DecodeString(raw)
+		if err == nil {
+			start, err = strconv.Atoi(string(decoded))
+		}
+		if err != nil || start < 0 {
+			return 0, nil, schemaViolation(map[string]string{"offset": "invalid offset"})
+		}
+	}
+
+	var matching []map[string]interface{}
+	for _, entity := range s.live(c.name) {
+		if matchesScope(entity, scope(parentKey, parent)) && matchesTags(entity, query.Get("tags")) {
+			matching = append(matching, entity)
+		}
+	}
+
+	data := []interface{}{}
+	for i := start; i < len(matching) && i < start+size; i++ {
+		data = append(data, s.render(matching[i]))
+	}
+
+	response := map[string]interface{}{"data": data, "next": nil}
+	if start+size < len(matching) {
+		offset := base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(start + size)))
+		next := r.URL.Query()
+		next.Set("offset", offset)
+		response["next"] = r.URL.Path + "?" + next.Encode()
+		response["offset"] = offset
+	}
+	return http.StatusOK, response, nil
+}
+
+// matchesTags filters on tags the way kong does, tags separated by a comma must all be set on an entity while tags
+// separated by a slash match when any of them is.
+func matchesTags(entity map[string]interface{}, filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	tags := map[string]bool{}
+	list, _ := entity["tags"].([]interface{})
+	for _, tag := range list {
+		tags[fmt.Sprint(tag)] = true
+	}
+
+	if strings.Contains(filter, "/") {
+		for _, tag := range strings.Split(filter, "/") {
+			if tags[tag] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, tag := range strings.Split(filter, ",") {
+		if !tags[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) create(c *collection, body map[string]interface{}, parentKey *foreignKey, parent map[string]interface{}) (int, interface{}, error) {
+	if id, ok := body["id"].(string); ok {
+		if !uuidPattern.MatchString(id) {
+			return 0, nil, schemaViolation(map[string]string{"id": "expected a valid UUID"})
+		}
+		if s.find(c.name, id, nil) != nil {
+			return 0, nil, primaryKeyViolation(id)
+		}
+	} else {
+		body["id"] = newUUID()
+	}
+	if parentKey != nil {
+		body[parentKey.field] = map[string]interface{}{"id": parent["id"]}
+	}
+
+	entity := map[string]interface{}{}
+	if c.defaults != nil {
+		entity = c.defaults()
+	}
+	mergeEntity(entity, body)
+	entity["created_at"] = s.now().Unix()
+
+	if err := s.store(c, entity, nil); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, s.render(entity), nil
+}
+
+func (s *Server) upsert(c *collection, idOrKey string, body map[string]interface{}, parentKey *foreignKey, parent map[string]interface{}) (int, interface{}, error) {
+	existing := s.find(c.name, idOrKey, scope(parentKey, parent))
+
+	if existing == nil {
+		if uuidPattern.MatchString(idOrKey) {
+			body["id"] = idOrKey
+		} else if c.endpointKey != "" {
+			body[c.endpointKey] = idOrKey
+			delete(body, "id")
+		} else {
+			return 0, nil, notFound()
+		}
+		_, entity, err := s.create(c, body, parentKey, parent)
+		return http.StatusOK, entity, err
+	}
+
+	// a put replaces the entity, only its id and creation time are kept.
+	entity := map[string]interface{}{}
+	if c.defaults != nil {
+		entity = c.defaults()
+	}
+	mergeEntity(entity, body)
+	entity["id"] = existing["id"]
+	entity["created_at"] = existing["created_at"]
+	if _, ok := entity[c.endpointKey]; !ok && c.endpointKey != "" && !uuidPattern.MatchString(idOrKey) {
+		entity[c.endpointKey] = idOrKey
+	}
+	if parentKey != nil {
+		entity[parentKey.field] = map[string]interface{}{"id": parent["id"]}
+	}
+
+	if err := s.store(c, entity, existing); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, s.render(entity), nil
+}
+
+func (s *Server) update(c *collection, idOrKey string, body map[string]interface{}, parentKey *foreignKey, parent map[string]interface{}) (int, interface{}, error) {
+	existing := s.find(c.name, idOrKey, scope(parentKey, parent))
+	if existing == nil {
+		return 0, nil, notFound()
+	}
+	if id, ok := body["id"]; ok && id != existing["id"] {
+		return 0, nil, schemaViolation(map[string]string{"id": "the id can not be changed"})
+	}
+
+	entity := deepCopy(existing).(map[string]interface{})
+	mergeEntity(entity, body)
+	if parentKey != nil {
+		entity[parentKey.field] = map[string]interface{}{"id": parent["id"]}
+	}
+
+	if err := s.store(c, entity, existing); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, s.render(entity), nil
+}
+
+// store validates an entity and saves it, replacing the existing entity it is an update of when that is not nil.
+func (s *Server) store(c *collection, entity map[string]interface{}, existing map[string]interface{}) error {
+	violations := map[string]string{}
+	for _, field := range c.required {
+		if !isSet(entity[field]) {
+			violations[field] = "required field missing"
+		}
+	}
+	if c.validate != nil {
+		for field, violation := range c.validate(entity) {
+			violations[field] = violation
+		}
+	}
+	if len(violations) > 0 {
+		return schemaViolation(violations)
+	}
+
+	for _, key := range c.foreignKeys {
+		if entity[key.field] == nil {
+			continue
+		}
+		id := foreignID(entity[key.field])
+		referenced := s.find(key.collection, id, nil)
+		if referenced == nil {
+			return foreignKeyViolation(key.field, id, key.collection)
+		}
+		// a foreign key may be given by name, it is always stored and returned by id.
+		entity[key.field] = map[string]interface{}{"id": referenced["id"]}
+	}
+
+	if c.prepare != nil {
+		c.prepare(entity)
+	}
+	if err := s.checkUnique(c, entity); err != nil {
+		return err
+	}
+
+	if c.name == "keyauth_credentials" {
+		// the ttl counts down from when it was set, updates leaving it alone keep the expiry.
+		if ttl, ok := toInt(entity["ttl"]); ok && ttl > 0 && entity["_ttl"] != ttl {
+			entity["_expires_at"] = s.now().Add(time.Duration(ttl) * time.Second)
+			entity["_ttl"] = ttl
+		}
+	}
+
+	if existing == nil {
+		s.entities[c.name] = append(s.entities[c.name], entity)
+		return nil
+	}
+	for i, candidate := range s.entities[c.name] {
+		if candidate["id"] == existing["id"] {
+			s.entities[c.name][i] = entity
+		}
+	}
+	return nil
+}
+
+func (s *Server) checkUnique(c *collection, entity map[string]interface{}) error {
+	for _, fields := range c.unique {
+		for _, other := range s.live(c.name) {
+			if other["id"] == entity["id"] {
+				continue
+			}
+			conflict := map[string]interface{}{}
+			for _, field := range fields {
+				if c.isForeignKey(field) {
+					if foreignID(entity[field]) != foreignID(other[field]) {
+						conflict = nil
+						break
+					}
+					if entity[field] != nil {
+						conflict[field] = entity[field]
+					}
+					continue
+				}
+				if entity[field] == nil || !reflect.DeepEqual(entity[field], other[field]) {
+					conflict = nil
+					break
+				}
+				conflict[field] = entity[field]
+			}
+			if conflict != nil {
+				return uniqueViolation(conflict)
+			}
+		}
+	}
+
+	// the SNIs of a certificate are entities of their own in kong, their names are unique across certificates.
+	if c.name == "certificates" {
+		for _, sni := range toSlice(entity["snis"]) {
+			for _, other := range s.live(c.name) {
+				if other["id"] == entity["id"] {
+					continue
+				}
+				for _, otherSNI := range toSlice(other["snis"]) {
+					if sni == otherSNI {
+						return uniqueViolation(map[string]interface{}{"snis": sni})
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *collection) isForeignKey(field string) bool {
+	for _, key := range c.foreignKeys {
+		if key.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) delete(c *collection, idOrKey string, parentKey *foreignKey, parent map[string]interface{}) (int, interface{}, error) {
+	entity := s.find(c.name, idOrKey, scope(parentKey, parent))
+	// deleting an entity that does not exist succeeds just like it does in kong.
+	if entity == nil {
+		return http.StatusNoContent, nil, nil
+	}
+
+	id := entity["id"]
+	for _, other := range sortedKeys(collections) {
+		key := collections[other].foreignKey(c.name)
+		if key == nil || key.cascade {
+			continue
+		}
+		for _, referrer := range s.live(other) {
+			if foreignID(referrer[key.field]) == id {
+				return 0, nil, referencedViolation(c.name, other)
+			}
+		}
+	}
+
+	s.remove(c.name, id)
+	return http.StatusNoContent, nil, nil
+}
+
+// remove deletes an entity along with the entities that cascade from it, delete has made sure nothing else refers to it.
+func (s *Server) remove(collectionName string, id interface{}) {
+	var kept []map[string]interface{}
+	for _, entity := range s.entities[collectionName] {
+		if entity["id"] != id {
+			kept = append(kept, entity)
+		}
+	}
+	s.entities[collectionName] = kept
+	delete(s.health, fmt.Sprint(id))
+
+	for _, other := range sortedKeys(collections) {
+		key := collections[other].foreignKey(collectionName)
+		if key == nil {
+			continue
+		}
+		for _, referrer := range s.live(other) {
+			if key.cascade && foreignID(referrer[key.field]) == id {
+				s.remove(other, referrer["id"])
+			}
+		}
+	}
+}
+
+func (s *Server) upstreamHealth(upstreamNameOrID string) (int, interface{}, error) {
+	upstream := s.find("upstreams", upstreamNameOrID, nil)
+	if upstream == nil {
+		return 0, nil, notFound()
+	}
+
+	data := []interface{}{}
+	for _, target := range s.live("targets") {
+		if foreignID(target["upstream"]) != upstream["id"] {
+			continue
+		}
+		health, ok := s.health[fmt.Sprint(target["id"])]
+		if !ok {
+			health = "HEALTHY"
+		}
+		if !healthchecksEnabled(upstream) {
+			health = "HEALTHCHECKS_OFF"
+		}
+		rendered := s.render(target)
+		rendered["health"] = health
+		data = append(data, rendered)
+	}
+	return http.StatusOK, map[string]interface{}{
+		"data":    data,
+		"next":    nil,
+		"node_id": "00000000-0000-0000-0000-000000000000",
+	}, nil
+}
+
+// healthchecksEnabled reports whether an upstream has active or passive health checks, kong reports the targets of an
+// upstream without them as HEALTHCHECKS_OFF whatever health they were given.
+func healthchecksEnabled(upstream map[string]interface{}) bool {
+	healthchecks, _ := upstream["healthchecks"].(map[string]interface{})
+	counters := map[string][]string{
+		"active.healthy":    {"interval"},
+		"active.unhealthy":  {"interval"},
+		"passive.healthy":   {"successes"},
+		"passive.unhealthy": {"tcp_failures", "timeouts", "http_failures"},
+	}
+	for path, fields := range counters {
+		parts := strings.Split(path, ".")
+		check, _ := healthchecks[parts[0]].(map[string]interface{})
+		state, _ := check[parts[1]].(map[string]interface{})
+		for _, field := range fields {
+			if value, ok := toNumber(state[field]); ok && value > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) setTargetHealth(upstreamNameOrID string, targetNameOrID string, health string) (int, interface{}, error) {
+	upstream := s.find("upstreams", upstreamNameOrID, nil)
+	if upstream == nil {
+		return 0, nil, notFound()
+	}
+	target := s.find("targets", targetNameOrID, map[string]interface{}{"upstream": upstream["id"]})
+	if target == nil {
+		return 0, nil, notFound()
+	}
+	s.health[fmt.Sprint(target["id"])] = strings.ToUpper(health)
+	return http.StatusNoContent, nil, nil
+}
+
+// render returns the entity as kong returns it, without the fields the fake keeps for itself.
+func (s *Server) render(entity map[string]interface{}) map[string]interface{} {
+	rendered := map[string]interface{}{}
+	for key, value := range entity {
+		if !strings.HasPrefix(key, "_") {
+			rendered[key] = deepCopy(value)
+		}
+	}
+	if expires, ok := entity["_expires_at"].(time.Time); ok {
+		rendered["ttl"] = int(expires.Sub(s.now()).Seconds())
+	}
+	return rendered
+}
+
+func decodeBody(r *http.Request) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if r.ContentLength == 0 {
+		return body, nil
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return nil, badRequest("Cannot parse JSON body")
+	}
+	return normalizeNumbers(body).(map[string]interface{}), nil
+}
+
+// normalizeNumbers turns the json numbers of a request into ints where they are whole, so they compare equal to the
+// defaults.
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	}
+	return value
+}
+
+// mergeEntity merges fields into an entity, nested maps such as the config of a plugin are merged field by field and
+// null values clear a field.
+func mergeEntity(entity map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range fields {
+		nested, isMap := value.(map[string]interface{})
+		existing, existingIsMap := entity[key].(map[string]interface{})
+		if isMap && existingIsMap && !isForeignKeyValue(nested) {
+			mergeEntity(existing, nested)
+			continue
+		}
+		if value == nil {
+			delete(entity, key)
+			continue
+		}
+		entity[key] = deepCopy(value)
+	}
+}
+
+func isForeignKeyValue(value map[string]interface{}) bool {
+	_, ok := value["id"]
+	return ok && len(value) == 1
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := map[string]interface{}{}
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	}
+	return value
+}
+
+// foreignID returns the id a foreign key refers to, foreign keys are sent either as {"id": ...} or as the plain id.
+func foreignID(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		id, _ := v["id"].(string)
+		return id
+	case string:
+		return v
+	}
+	return ""
+}
+
+func isSet(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toSlice(value interface{}) []interface{} {
+	s, _ := value.([]interface{})
+	return s
+}
+
+func describe(value interface{}) string {
+	if _, isMap := value.(map[string]interface{}); isMap {
+		return fmt.Sprintf("{id=\"%s\"}", foreignID(value))
+	}
+	return fmt.Sprintf("%q", fmt.Sprint(value))
+}
+
+// sortedKeys returns the keys of a map keyed by string in order, so errors and cascades are deterministic.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
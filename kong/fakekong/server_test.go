@@ -0,0 +1,309 @@
+package fakekong
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+)
+
+func newClient(t *testing.T) (*Server, *kong.Client) {
+	server := NewServer("2.5.0")
+	t.Cleanup(server.Close)
+
+	client, err := kong.NewClient(kong.String(server.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, client
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	apiErr, ok := err.(*kong.APIError)
+	if !ok || apiErr.Code() != status {
+		t.Fatalf("expected an error with status %d, got %v", status, err)
+	}
+}
+
+func TestServiceDefaultsAndUpdates(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	service, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("svc"), Host: kong.String("example.com")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.ID == nil || !uuidPattern.MatchString(*service.ID) || service.CreatedAt == nil {
+		t.Errorf("expected a generated id and creation time, got %v %v", service.ID, service.CreatedAt)
+	}
+	if *service.Protocol != "http" || *service.Port != 80 || *service.Retries != 5 || *service.ConnectTimeout != 60000 {
+		t.Errorf("expected kong's defaults, got %+v", service)
+	}
+
+	if _, err := client.Services.Update(ctx, &kong.Service{ID: service.ID, Port: kong.Int(8080)}); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := client.Services.Get(ctx, kong.String("svc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updated.Port != 8080 || *updated.Host != "example.com" {
+		t.Errorf("expected the update to be merged, got %+v", updated)
+	}
+
+	if _, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("svc"), Host: kong.String("other.com")}); err == nil {
+		t.Fatal("expected a duplicate name to be rejected")
+	} else {
+		assertStatus(t, err, http.StatusConflict)
+	}
+
+	if _, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("no-host")}); err == nil {
+		t.Fatal("expected a service without a host to be rejected")
+	} else {
+		assertStatus(t, err, http.StatusBadRequest)
+	}
+
+	if _, err := client.Services.Get(ctx, kong.String("missing")); !kong.IsNotFoundErr(err) {
+		t.Errorf("expected a missing service to be not found, got %v", err)
+	}
+}
+
+func TestPaginationAndTags(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		tags := []*string{kong.String("all")}
+		if i%2 == 0 {
+			tags = append(tags, kong.String("even"))
+		}
+		if _, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String(string(rune('a' + i))), Tags: tags}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, next, err := client.Consumers.List(ctx, &kong.ListOpt{Size: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || next == nil || *page[0].Username != "a" {
+		t.Fatalf("expected a first page of two consumers, got %d", len(page))
+	}
+	var usernames []string
+	for next != nil {
+		for _, consumer := range page {
+			usernames = append(usernames, *consumer.Username)
+		}
+		if page, next, err = client.Consumers.List(ctx, next); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, consumer := range page {
+		usernames = append(usernames, *consumer.Username)
+	}
+	if len(usernames) != 5 || usernames[4] != "e" {
+		t.Errorf("expected to page through all consumers in order, got %v", usernames)
+	}
+
+	even, _, err := client.Consumers.List(ctx, &kong.ListOpt{Tags: kong.StringSlice("all", "even"), MatchAllTags: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(even) != 3 {
+		t.Errorf("expected three consumers tagged all and even, got %d", len(even))
+	}
+
+	either, _, err := client.Consumers.List(ctx, &kong.ListOpt{Tags: kong.StringSlice("even", "missing")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(either) != 3 {
+		t.Errorf("expected three consumers tagged even or missing, got %d", len(either))
+	}
+}
+
+func TestDeleteRestrictsAndCascades(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	service, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("svc"), Host: kong.String("example.com")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err := client.Routes.CreateInService(ctx, service.ID, &kong.Route{Paths: kong.StringSlice("/")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Plugins.Create(ctx, &kong.Plugin{Name: kong.String("cors"), Route: &kong.Route{ID: route.ID}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Services.Delete(ctx, service.ID); err == nil {
+		t.Fatal("expected deleting a service with routes to be refused")
+	} else {
+		assertStatus(t, err, http.StatusBadRequest)
+	}
+
+	if err := client.Routes.Delete(ctx, route.ID); err != nil {
+		t.Fatal(err)
+	}
+	plugins, err := client.Plugins.ListAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 0 {
+		t.Errorf("expected the plugin of the route to be deleted with it, got %d plugins", len(plugins))
+	}
+	if err := client.Services.Delete(ctx, service.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Routes.Create(ctx, &kong.Route{Paths: kong.StringSlice("/"), Service: &kong.Service{ID: service.ID}}); err == nil {
+		t.Fatal("expected a route referring to a deleted service to be rejected")
+	} else {
+		assertStatus(t, err, http.StatusBadRequest)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	server, client := newClient(t)
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("alice")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basicAuth, err := client.BasicAuths.Create(ctx, kong.String("alice"), &kong.BasicAuth{Username: kong.String("alice"), Password: kong.String("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha1.Sum([]byte("secret" + *consumer.ID))
+	if *basicAuth.Password != hex.EncodeToString(hash[:]) {
+		t.Errorf("expected the password to be stored hashed, got %s", *basicAuth.Password)
+	}
+
+	keyAuth, err := client.KeyAuths.Create(ctx, consumer.ID, &kong.KeyAuth{TTL: kong.Int(60)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyAuth.Key == nil || *keyAuth.Key == "" {
+		t.Error("expected a key to be generated")
+	}
+	if _, err := client.KeyAuths.Create(ctx, consumer.ID, &kong.KeyAuth{Key: keyAuth.Key}); err == nil {
+		t.Fatal("expected a duplicate key to be rejected")
+	} else {
+		assertStatus(t, err, http.StatusConflict)
+	}
+
+	server.now = func() time.Time { return time.Now().Add(time.Minute) }
+	if _, err := client.KeyAuths.Get(ctx, consumer.ID, keyAuth.ID); !kong.IsNotFoundErr(err) {
+		t.Errorf("expected the key to expire after its ttl, got %v", err)
+	}
+	server.now = time.Now
+
+	if _, err := client.ACLs.Create(ctx, consumer.ID, &kong.ACLGroup{Group: kong.String("admins")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Consumers.Delete(ctx, consumer.ID); err != nil {
+		t.Fatal(err)
+	}
+	acls, err := client.ACLs.ListAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basicAuths, err := client.BasicAuths.ListAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acls) != 0 || len(basicAuths) != 0 {
+		t.Errorf("expected the credentials to be deleted with their consumer, got %d acls and %d basic auths", len(acls), len(basicAuths))
+	}
+}
+
+func TestUpstreamsAndTargets(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	upstream, err := client.Upstreams.Create(ctx, &kong.Upstream{Name: kong.String("upstream")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upstream.Healthchecks == nil || upstream.Healthchecks.Active == nil || *upstream.Healthchecks.Active.HTTPPath != "/" {
+		t.Errorf("expected the default healthchecks, got %+v", upstream.Healthchecks)
+	}
+
+	updated, err := client.Upstreams.Update(ctx, &kong.Upstream{
+		ID:           upstream.ID,
+		Healthchecks: &kong.Healthcheck{Active: &kong.ActiveHealthcheck{Concurrency: kong.Int(5)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updated.Healthchecks.Active.Concurrency != 5 || *updated.Healthchecks.Active.HTTPPath != "/" {
+		t.Errorf("expected the healthchecks to be merged, got %+v", updated.Healthchecks.Active)
+	}
+
+	target, err := client.Targets.Create(ctx, upstream.Name, &kong.Target{Target: kong.String("10.0.0.1:80")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *target.Weight != 100 || *target.Upstream.ID != *upstream.ID {
+		t.Errorf("expected the default weight and the upstream id, got %+v", target)
+	}
+	if _, err := client.Targets.Create(ctx, upstream.ID, &kong.Target{Target: kong.String("10.0.0.1:80")}); err == nil {
+		t.Fatal("expected a duplicate target to be rejected")
+	} else {
+		assertStatus(t, err, http.StatusConflict)
+	}
+
+	if err := client.Targets.MarkUnhealthy(ctx, upstream.ID, target); err != nil {
+		t.Fatal(err)
+	}
+	health, err := client.UpstreamNodeHealth.ListAll(ctx, upstream.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(health) != 1 || *health[0].Health != "HEALTHCHECKS_OFF" {
+		t.Errorf("expected the health of a target without health checks to be off, got %+v", health)
+	}
+
+	passive := &kong.PassiveHealthcheck{Unhealthy: &kong.Unhealthy{HTTPFailures: kong.Int(1)}}
+	if _, err := client.Upstreams.Update(ctx, &kong.Upstream{ID: upstream.ID, Healthchecks: &kong.Healthcheck{Passive: passive}}); err != nil {
+		t.Fatal(err)
+	}
+	health, err = client.UpstreamNodeHealth.ListAll(ctx, upstream.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(health) != 1 || *health[0].Health != "UNHEALTHY" {
+		t.Errorf("expected the target to be unhealthy, got %+v", health)
+	}
+
+	if err := client.Upstreams.Delete(ctx, upstream.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Targets.ListAll(ctx, upstream.ID); !kong.IsNotFoundErr(err) {
+		t.Errorf("expected the targets of a deleted upstream to be gone, got %v", err)
+	}
+}
+
+func TestCertificateSNIsAreUnique(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	certificate := &kong.Certificate{Cert: kong.String("cert"), Key: kong.String("key"), SNIs: kong.StringSlice("example.com")}
+	if _, err := client.Certificates.Create(ctx, certificate); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Certificates.Create(ctx, &kong.Certificate{Cert: kong.String("cert"), Key: kong.String("key"), SNIs: kong.StringSlice("example.com")}); err == nil {
+		t.Fatal("expected an SNI used by another certificate to be rejected")
+	} else {
+		assertStatus(t, err, http.StatusConflict)
+	}
+}
@@ -0,0 +1,126 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+// kongKeySet is a Kong 3.x key set, go-kong does not model keys yet.
+type kongKeySet struct {
+	CreatedAt *int      `json:"created_at,omitempty"`
+	ID        *string   `json:"id,omitempty"`
+	Name      *string   `json:"name,omitempty"`
+	Tags      []*string `json:"tags,omitempty"`
+}
+
+// kongKey is a Kong 3.x key given either as a JWK or as a PEM key pair.
+type kongKey struct {
+	CreatedAt *int        `json:"created_at,omitempty"`
+	ID        *string     `json:"id,omitempty"`
+	Name      *string     `json:"name,omitempty"`
+	KID       *string     `json:"kid,omitempty"`
+	Set       *kongKeySet `json:"set,omitempty"`
+	JWK       *string     `json:"jwk,omitempty"`
+	PEM       *kongKeyPEM `json:"pem,omitempty"`
+	Tags      []*string   `json:"tags,omitempty"`
+}
+
+type kongKeyPEM struct {
+	PublicKey  *string `json:"public_key,omitempty"`
+	PrivateKey *string `json:"private_key,omitempty"`
+}
+
+func createKongKeySet(ctx context.Context, client *kong.Client, keySet *kongKeySet) (*kongKeySet, error) {
+	created := &kongKeySet{}
+	if err := doKongRequest(ctx, client, "POST", "/key-sets", keySet, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func updateKongKeySet(ctx context.Context, client *kong.Client, keySet *kongKeySet) (*kongKeySet, error) {
+	if keySet.ID == nil {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	updated := &kongKeySet{}
+	if err := doKongRequest(ctx, client, "PATCH", "/key-sets/"+url.PathEscape(*keySet.ID), keySet, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func getKongKeySet(ctx context.Context, client *kong.Client, nameOrID string) (*kongKeySet, error) {
+	keySet := &kongKeySet{}
+	if err := doKongRequest(ctx, client, "GET", "/key-sets/"+url.PathEscape(nameOrID), nil, keySet); err != nil {
+		return nil, err
+	}
+	return keySet, nil
+}
+
+func deleteKongKeySet(ctx context.Context, client *kong.Client, nameOrID string) error {
+	return doKongRequest(ctx, client, "DELETE", "/key-sets/"+url.PathEscape(nameOrID), nil, nil)
+}
+
+func createKongKey(ctx context.Context, client *kong.Client, key *kongKey) (*kongKey, error) {
+	created := &kongKey{}
+	if err := doKongRequest(ctx, client, "POST", "/keys", key, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func updateKongKey(ctx context.Context, client *kong.Client, key *kongKey) (*kongKey, error) {
+	if key.ID == nil {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	updated := &kongKey{}
+	if err := doKongRequest(ctx, client, "PATCH", "/keys/"+url.PathEscape(*key.ID), key, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func getKongKey(ctx context.Context, client *kong.Client, nameOrID string) (*kongKey, error) {
+	key := &kongKey{}
+	if err := doKongRequest(ctx, client, "GET", "/keys/"+url.PathEscape(nameOrID), nil, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func deleteKongKey(ctx context.Context, client *kong.Client, nameOrID string) error {
+	return doKongRequest(ctx, client, "DELETE", "/keys/"+url.PathEscape(nameOrID), nil, nil)
+}
+
+// suppressEquivalentJWKDiff ignores a JWK that kong returns with its members reordered or reformatted.
+func suppressEquivalentJWKDiff(k, old, new string, d *schema.ResourceData) bool {
+	if suppressHashedSecretDiff(k, old, new, d) {
+		return true
+	}
+
+	var oldJWK, newJWK map[string]interface{}
+	if err := json.Unmarshal([]byte(old), &oldJWK); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newJWK); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldJWK, newJWK)
+}
+
+func validateJWK(jwkI interface{}, _ string) ([]string, []error) {
+	jwk := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(jwkI.(string)), &jwk); err != nil {
+		return nil, []error{fmt.Errorf("jwk is not a valid JSON object: %v", err)}
+	}
+	if _, ok := jwk["kty"]; !ok {
+		return nil, []error{fmt.Errorf("jwk is missing the kty member")}
+	}
+	return nil, nil
+}
@@ -1,20 +1,37 @@
 package kong
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"os"
+	"time"
 
+	"github.com/blang/semver/v4"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kong/go-kong/kong"
 )
 
+// versionDetectionTimeout bounds detecting the kong version so an unreachable node does not hang configuring the
+// provider.
+const versionDetectionTimeout = 10 * time.Second
+
+// defaultResourceTimeout is how long a resource operation may take when its timeouts block does not say otherwise.
+const defaultResourceTimeout = 5 * time.Minute
+
 type config struct {
 	adminClient           *kong.Client
 	strictPlugins         bool
 	strictConsumerPlugins bool
+	writeOnlySecrets      bool
+	// kongVersion is the version of the kong node the provider talks to, nil when it could not be detected.
+	kongVersion    *semver.Version
+	kongEnterprise bool
 }
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"kong_admin_uri": {
 				Type:        schema.TypeString,
@@ -64,21 +81,74 @@ func Provider() *schema.Provider {
 				DefaultFunc: envDefaultFuncWithDefault("STRICT_PLUGINS_MATCH", "false"),
 				Description: "Should plugins `config_json` field strictly match plugin configuration",
 			},
+			"write_only_secrets": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    false,
+				DefaultFunc: envDefaultFuncWithDefault("WRITE_ONLY_SECRETS", "false"),
+				Description: "Store only a SHA-256 hash of private keys, passwords and secrets in state",
+			},
+			"db_less": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    false,
+				DefaultFunc: envDefaultFuncWithDefault("KONG_DB_LESS", "false"),
+				Description: "Manage a DB-less kong by posting a declarative configuration to /config instead of writing entities one by one",
+			},
+			"db_less_format_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    false,
+				DefaultFunc: envDefaultFuncWithDefault("KONG_DB_LESS_FORMAT_VERSION", defaultDeclarativeFormatVersion),
+				Description: "The _format_version of the declarative configuration, 2.1 for kong 2.x and 3.0 for kong 3.x",
+			},
+			"max_requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     false,
+				DefaultFunc:  envDefaultFuncWithDefault("KONG_MAX_REQUESTS_PER_SECOND", "0"),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The most requests a second the provider sends to the admin api across all resources, 0 for no limit",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     false,
+				DefaultFunc:  envDefaultFuncWithDefault("KONG_MAX_CONCURRENT_REQUESTS", "0"),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The most requests the provider has in flight to the admin api at a time across all resources, 0 for no limit",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				DefaultFunc:  envDefaultFuncWithDefault("KONG_REQUEST_TIMEOUT", "0"),
+				ValidateFunc: validateDuration,
+				Description:  "How long a single request to the admin api may take such as 30s or 2m, 0 for no limit",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"kong_certificate":         resourceKongCertificate(),
-			"kong_consumer":            resourceKongConsumer(),
-			"kong_consumer_acl":        resourceKongConsumerACL(),
-			"kong_consumer_basic_auth": resourceKongConsumerBasicAuth(),
-			"kong_consumer_key_auth":   resourceKongConsumerKeyAuth(),
-			"kong_consumer_oauth2":     resourceKongConsumerOAuth2(),
-			"kong_plugin":              resourceKongPlugin(),
-			"kong_upstream":            resourceKongUpstream(),
-			"kong_target":              resourceKongTarget(),
-			"kong_service":             resourceKongService(),
-			"kong_route":               resourceKongRoute(),
-			"kong_consumer_jwt_auth":   resourceKongConsumerJWTAuth(),
+			"kong_certificate":           resourceKongCertificate(),
+			"kong_consumer":              resourceKongConsumer(),
+			"kong_consumer_acl":          resourceKongConsumerACL(),
+			"kong_consumer_basic_auth":   resourceKongConsumerBasicAuth(),
+			"kong_consumer_key_auth":     resourceKongConsumerKeyAuth(),
+			"kong_consumer_key_rotation": resourceKongConsumerKeyRotation(),
+			"kong_consumer_oauth2":       resourceKongConsumerOAuth2(),
+			"kong_plugin":                resourceKongPlugin(),
+			"kong_upstream":              resourceKongUpstream(),
+			"kong_target":                resourceKongTarget(),
+			"kong_service":               resourceKongService(),
+			"kong_route":                 resourceKongRoute(),
+			"kong_consumer_jwt_auth":     resourceKongConsumerJWTAuth(),
+			"kong_key":                   resourceKongKey(),
+			"kong_key_set":               resourceKongKeySet(),
+			"kong_vault":                 resourceKongVault(),
+			"kong_consumer_group":        resourceKongConsumerGroup(),
+			"kong_consumer_group_member": resourceKongConsumerGroupMember(),
+			"kong_consumer_group_plugin": resourceKongConsumerGroupPlugin(),
+			"kong_consumer_acls":         resourceKongConsumerACLs(),
 		},
 
 		//DataSourcesMap: map[string]*schema.Resource{
@@ -90,6 +160,11 @@ func Provider() *schema.Provider {
 		//},
 		ConfigureFunc: providerConfigure,
 	}
+
+	for _, resource := range provider.ResourcesMap {
+		redactResource(resource)
+	}
+	return provider
 }
 
 func envDefaultFuncWithDefault(key string, defaultValue string) schema.SchemaDefaultFunc {
@@ -109,14 +184,23 @@ func envDefaultFuncWithDefault(key string, defaultValue string) schema.SchemaDef
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	kongConfig := &Config{
-		Address:            d.Get("kong_admin_uri").(string),
-		Username:           d.Get("kong_admin_username").(string),
-		Password:           d.Get("kong_admin_password").(string),
-		InsecureSkipVerify: d.Get("tls_skip_verify").(bool),
-		APIKey:             d.Get("kong_api_key").(string),
-		AdminToken:         d.Get("kong_admin_token").(string),
-		Workspace:          d.Get("kong_workspace").(string),
+		Address:                  d.Get("kong_admin_uri").(string),
+		Username:                 d.Get("kong_admin_username").(string),
+		Password:                 d.Get("kong_admin_password").(string),
+		InsecureSkipVerify:       d.Get("tls_skip_verify").(bool),
+		APIKey:                   d.Get("kong_api_key").(string),
+		AdminToken:               d.Get("kong_admin_token").(string),
+		Workspace:                d.Get("kong_workspace").(string),
+		DBLess:                   d.Get("db_less").(bool),
+		DeclarativeFormatVersion: d.Get("db_less_format_version").(string),
+		MaxRequestsPerSecond:     d.Get("max_requests_per_second").(int),
+		MaxConcurrentRequests:    d.Get("max_concurrent_requests").(int),
+	}
+	requestTimeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid request_timeout: %v", err)
 	}
+	kongConfig.RequestTimeout = requestTimeout
 
 	client, err := GetKongClient(*kongConfig)
 	if err != nil {
@@ -124,8 +208,20 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	}
 
 	config := &config{
-		adminClient:   client,
-		strictPlugins: d.Get("strict_plugins_match").(bool),
+		adminClient:      client,
+		strictPlugins:    d.Get("strict_plugins_match").(bool),
+		writeOnlySecrets: d.Get("write_only_secrets").(bool),
+	}
+
+	// the version is detected once so resources can reject attributes the node does not support with a clear error.
+	// Not reaching kong here is not fatal, the resources report it when they talk to it.
+	ctx, cancel := context.WithTimeout(context.Background(), versionDetectionTimeout)
+	defer cancel()
+	root, err := client.Root(ctx)
+	if err != nil {
+		log.Printf("[WARN] Could not detect the kong version, attributes are not checked against it: %v", err)
+	} else if config.kongVersion, config.kongEnterprise, err = parseKongVersion(root); err != nil {
+		log.Printf("[WARN] Could not parse the kong version, attributes are not checked against it: %v", err)
 	}
 
 	return config, nil
@@ -2,13 +2,19 @@ package kong
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/kevholditch/terraform-provider-kong/kong/containers"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
 )
 
 const defaultKongVersion = "2.5.0-ubuntu"
@@ -18,8 +24,17 @@ const EnvKongAdminPassword = "KONG_ADMIN_PASSWORD"
 const defaultKongRepository = "kong"
 const defaultKongLicense = ""
 const providerNameKong = "kong"
+const EnvKongTestDocker = "KONG_TEST_DOCKER"
+const EnvKongTestDatabase = "KONG_TEST_DATABASE"
 
 var (
+	// testAgainstFake is set when the tests run against the in-memory fake of the admin api rather than kong in docker.
+	testAgainstFake bool
+	// testKongDatabase is the storage mode of the kong the tests run against.
+	testKongDatabase = containers.DatabasePostgres
+	// testKongVersion is the version of the kong the tests run against, the tests run once per version in KONG_VERSION.
+	testKongVersion = defaultKongVersion
+
 	testAccProviders         map[string]*schema.Provider
 	testAccProvider          *schema.Provider
 	testAccProviderFactories map[string]func() (*schema.Provider, error)
@@ -67,15 +82,79 @@ func TestProvider_configure_strict(t *testing.T) {
 	}
 }
 
-func TestMain(m *testing.M) {
+// skipUnlessEnterprise skips tests of Kong Enterprise entities unless the tests run against a licensed image, set
+// KONG_REPOSITORY to kong/kong-gateway and KONG_LICENSE_DATA to run them.
+func skipUnlessEnterprise(t *testing.T) {
+	if testAgainstFake {
+		t.Skipf("requires Kong Enterprise, set %s=1 to run against kong in docker", EnvKongTestDocker)
+	}
+	if GetEnvVarOrDefault("KONG_LICENSE_DATA", defaultKongLicense) == "" {
+		t.Skip("requires Kong Enterprise, set KONG_REPOSITORY and KONG_LICENSE_DATA")
+	}
+}
 
-	testContext := containers.StartKong(defaultKongRepository, GetEnvVarOrDefault("KONG_VERSION", defaultKongVersion), defaultKongLicense)
+// skipUnlessKongVersion skips a test unless the kong under test is at least minimum and older than maximum, either
+// bound can be left empty. Image tags such as 2.8.4-ubuntu are compared by their version alone.
+func skipUnlessKongVersion(t *testing.T, minimum string, maximum string) {
+	if minimum != "" && kongVersionBefore(t, testKongVersion, minimum) {
+		t.Skipf("requires Kong %s or later, running against %s", minimum, testKongVersion)
+	}
+	if maximum != "" && !kongVersionBefore(t, testKongVersion, maximum) {
+		t.Skipf("requires Kong older than %s, running against %s", maximum, testKongVersion)
+	}
+}
 
-	err := os.Setenv(EnvKongAdminHostAddress, testContext.KongHostAddress)
+// kongVersionBefore reports whether kong version a is older than version b.
+func kongVersionBefore(t *testing.T, a string, b string) bool {
+	versionA, err := kong.ParseSemanticVersion(a)
 	if err != nil {
-		log.Fatalf("Could not set kong host address env variable: %v", err)
+		t.Fatalf("could not parse kong version %s: %v", a, err)
 	}
-	err = os.Setenv(EnvKongAdminPassword, "AnUsername")
+	versionB, err := kong.ParseSemanticVersion(b)
+	if err != nil {
+		t.Fatalf("could not parse kong version %s: %v", b, err)
+	}
+	versionA.Pre, versionA.Build = nil, nil
+	versionB.Pre, versionB.Build = nil, nil
+	return versionA.LT(versionB)
+}
+
+// skipAgainstFake skips a test of an entity the in-memory fake of the admin api does not serve.
+func skipAgainstFake(t *testing.T, entity string) {
+	if testAgainstFake {
+		t.Skipf("%s are not served by the fake admin api, set %s=1 to run against kong in docker", entity, EnvKongTestDocker)
+	}
+}
+
+// skipInDatabase skips a test of a resource kong does not support in the storage mode the tests run against.
+func skipInDatabase(t *testing.T, reason string, databases ...string) {
+	for _, database := range databases {
+		if testKongDatabase == database {
+			t.Skipf("not supported with %s=%s: %s", EnvKongTestDatabase, database, reason)
+		}
+	}
+}
+
+// TestMain runs the tests against an in-memory fake of the admin api, set KONG_TEST_DOCKER=1 to start kong in docker
+// instead. KONG_TEST_DATABASE picks the storage mode of kong in docker: postgres, cassandra or off for DB-less kong.
+// KONG_VERSION takes a comma separated list of versions, the tests run against each of them in turn.
+func TestMain(m *testing.M) {
+
+	flag.Parse()
+	if sweep := flag.Lookup("sweep"); sweep != nil && sweep.Value.String() != "" {
+		// the sweepers clean up the kong at KONG_ADMIN_ADDR, none is started for them. resource.TestMain only exits
+		// when a sweeper fails.
+		resource.TestMain(m)
+		os.Exit(0)
+	}
+
+	testKongDatabase = GetEnvVarOrDefault(EnvKongTestDatabase, containers.DatabasePostgres)
+	useDocker, _ := strconv.ParseBool(GetEnvVarOrDefault(EnvKongTestDocker, "false"))
+	if !useDocker && testKongDatabase != containers.DatabasePostgres {
+		log.Fatalf("%s=%s needs kong in docker, set %s=1", EnvKongTestDatabase, testKongDatabase, EnvKongTestDocker)
+	}
+
+	err := os.Setenv(EnvKongAdminPassword, "AnUsername")
 	if err != nil {
 		log.Fatalf("Could not set kong admin username env variable: %v", err)
 	}
@@ -83,10 +162,42 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		log.Fatalf("Could not set kong admin password env variable: %v", err)
 	}
+	if testKongDatabase == containers.DatabaseOff {
+		// DB-less kong only takes its configuration through /config, the provider has to run in db_less mode.
+		if err := os.Setenv("KONG_DB_LESS", "true"); err != nil {
+			log.Fatalf("Could not set kong db less env variable: %v", err)
+		}
+	}
 
-	code := m.Run()
-
-	containers.StopKong(testContext)
+	code := 0
+	for _, kongVersion := range strings.Split(GetEnvVarOrDefault("KONG_VERSION", defaultKongVersion), ",") {
+		testKongVersion = strings.TrimSpace(kongVersion)
+
+		var address string
+		var stop func()
+		if useDocker {
+			testContext := containers.StartKongWithDatabase(testKongDatabase, GetEnvVarOrDefault("KONG_REPOSITORY", defaultKongRepository), testKongVersion, GetEnvVarOrDefault("KONG_LICENSE_DATA", defaultKongLicense))
+			address = testContext.KongHostAddress
+			stop = func() { containers.StopKong(testContext) }
+		} else {
+			server := fakekong.NewServer(strings.SplitN(testKongVersion, "-", 2)[0])
+			testAgainstFake = true
+			address = server.URL
+			stop = server.Close
+		}
+
+		err = os.Setenv(EnvKongAdminHostAddress, address)
+		if err != nil {
+			log.Fatalf("Could not set kong host address env variable: %v", err)
+		}
+
+		log.Printf("Running tests against Kong %s", testKongVersion)
+		if versionCode := m.Run(); versionCode != 0 {
+			code = versionCode
+		}
+
+		stop()
+	}
 
 	os.Exit(code)
 
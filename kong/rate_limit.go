@@ -0,0 +1,75 @@
+package kong
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimitRoundTripper caps the rate and the number of concurrent calls to the admin api. GetKongClient creates one
+// per configured provider so the limits hold across all of its resources, whatever the parallelism of terraform.
+type RateLimitRoundTripper struct {
+	rt http.RoundTripper
+	// interval is the time between the start of two requests, zero when the rate is not limited.
+	interval time.Duration
+	// slots holds a token per request in flight, nil when the concurrency is not capped.
+	slots chan struct{}
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRateLimitRoundTripper returns a round tripper sending at most requestsPerSecond requests a second and at most
+// concurrentRequests at a time through rt, or rt itself when neither is set.
+func NewRateLimitRoundTripper(rt http.RoundTripper, requestsPerSecond int, concurrentRequests int) http.RoundTripper {
+	if requestsPerSecond <= 0 && concurrentRequests <= 0 {
+		return rt
+	}
+
+	t := &RateLimitRoundTripper{rt: rt}
+	if requestsPerSecond > 0 {
+		t.interval = time.Second / time.Duration(requestsPerSecond)
+	}
+	if concurrentRequests > 0 {
+		t.slots = make(chan struct{}, concurrentRequests)
+	}
+	return t
+}
+
+// RoundTrip satisfies the RoundTripper interface. A request whose context ends while it waits is not sent.
+func (t *RateLimitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// admin api responses are small, the slot is freed once the response arrives rather than when its body is
+		// closed so a caller that never closes a body cannot exhaust the slots.
+		defer func() { <-t.slots }()
+	}
+
+	if t.interval > 0 {
+		t.mu.Lock()
+		start := time.Now()
+		if t.next.After(start) {
+			start = t.next
+		}
+		t.next = start.Add(t.interval)
+		t.mu.Unlock()
+
+		if wait := time.Until(start); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return t.rt.RoundTrip(req)
+}
@@ -0,0 +1,105 @@
+package kong
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+)
+
+// countingRoundTripper answers every request after a delay and records how many were in flight at once.
+type countingRoundTripper struct {
+	delay    time.Duration
+	inFlight int32
+	peak     int32
+}
+
+func (t *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	inFlight := atomic.AddInt32(&t.inFlight, 1)
+	for {
+		peak := atomic.LoadInt32(&t.peak)
+		if inFlight <= peak || atomic.CompareAndSwapInt32(&t.peak, peak, inFlight) {
+			break
+		}
+	}
+	time.Sleep(t.delay)
+	atomic.AddInt32(&t.inFlight, -1)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func sendConcurrently(t *testing.T, rt http.RoundTripper, requests int) {
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "http://localhost:8001/services", nil)
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRateLimitRoundTripperCapsConcurrency(t *testing.T) {
+	backend := &countingRoundTripper{delay: 10 * time.Millisecond}
+	sendConcurrently(t, NewRateLimitRoundTripper(backend, 0, 2), 10)
+
+	if backend.peak != 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", backend.peak)
+	}
+}
+
+func TestRateLimitRoundTripperLimitsRate(t *testing.T) {
+	backend := &countingRoundTripper{}
+	start := time.Now()
+	sendConcurrently(t, NewRateLimitRoundTripper(backend, 50, 0), 6)
+
+	// the first request goes straight away, the other five are spaced 20ms apart.
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected 6 requests at 50 a second to take at least 100ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimitRoundTripperGivesUpWithTheContext(t *testing.T) {
+	rt := NewRateLimitRoundTripper(&countingRoundTripper{}, 1, 0)
+
+	req, _ := http.NewRequest("GET", "http://localhost:8001/services", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := rt.RoundTrip(req.WithContext(ctx)); err != context.DeadlineExceeded {
+		t.Errorf("expected the request waiting for the rate limit to end with its context, got %v", err)
+	}
+
+	if rt := NewRateLimitRoundTripper(http.DefaultTransport, 0, 0); rt != http.DefaultTransport {
+		t.Error("expected no limits when none are set")
+	}
+}
+
+func TestProviderConfiguresRateLimits(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	for _, raw := range []map[string]interface{}{
+		{"kong_admin_uri": server.URL},
+		{"kong_admin_uri": server.URL, "max_requests_per_second": 100, "max_concurrent_requests": 4},
+	} {
+		p := Provider()
+		if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if _, err := p.Meta().(*config).adminClient.Services.ListAll(context.Background()); err != nil {
+			t.Errorf("expected the client configured with %v to work, got %v", raw, err)
+		}
+	}
+}
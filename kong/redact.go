@@ -0,0 +1,227 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/textproto"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// redactedValue stands in for a secret in errors and logs.
+const redactedValue = "(redacted)"
+
+// sensitiveFields are the fields holding secrets, both in the attributes of the resources and in the entities the
+// provider sends to kong.
+var sensitiveFields = map[string]bool{
+	"password":        true,
+	"key":             true,
+	"key_alt":         true,
+	"private_key":     true,
+	"private_key_alt": true,
+	"secret":          true,
+	"client_secret":   true,
+	"jwk":             true,
+	"token":           true,
+	"active_key":      true,
+	"retiring_keys":   true,
+}
+
+// sensitiveHeaders carry the credentials of the admin api.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":    true,
+	"Apikey":           true,
+	"Kong-Admin-Token": true,
+}
+
+// redactedRequest renders a request sent to kong for an error message, with its secrets redacted.
+func redactedRequest(request interface{}) string {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Sprintf("%T", request)
+	}
+	return string(redactJSON(body))
+}
+
+// redactJSON redacts the values of the sensitive fields of a json document. A body that is not json could hold
+// anything so it is redacted as a whole.
+func redactJSON(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return []byte(fmt.Sprintf("%s %d bytes", redactedValue, len(body)))
+	}
+	redacted, err := json.Marshal(redactValue(document))
+	if err != nil {
+		return []byte(fmt.Sprintf("%s %d bytes", redactedValue, len(body)))
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+		for field, fieldValue := range value {
+			if sensitiveFields[field] && fieldValue != nil {
+				redacted[field] = redactedValue
+			} else {
+				redacted[field] = redactValue(fieldValue)
+			}
+		}
+		// a kong error quotes the values of the fields it is about in its message.
+		if fields, ok := value["fields"].(map[string]interface{}); ok && value["message"] != nil && hasSensitiveField(fields) {
+			redacted["message"] = redactedValue
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, element := range value {
+			redacted[i] = redactValue(element)
+		}
+		return redacted
+	case string:
+		// the declarative configuration posted to /config is a json document embedded in a string.
+		var document map[string]interface{}
+		if strings.HasPrefix(value, "{") && json.Unmarshal([]byte(value), &document) == nil {
+			if redacted, err := json.Marshal(redactValue(document)); err == nil {
+				return string(redacted)
+			}
+			return redactedValue
+		}
+	}
+	return value
+}
+
+func hasSensitiveField(fields map[string]interface{}) bool {
+	for field := range fields {
+		if sensitiveFields[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHTTPDump redacts the credential headers and the body of a request or response dumped by httputil.
+func redactHTTPDump(dump []byte) []byte {
+	separator := []byte("\r\n\r\n")
+	head, body := dump, []byte(nil)
+	if i := bytes.Index(dump, separator); i >= 0 {
+		head, body = dump[:i], dump[i+len(separator):]
+	}
+
+	lines := strings.Split(string(head), "\r\n")
+	for i, line := range lines {
+		if name := strings.SplitN(line, ":", 2)[0]; i > 0 && sensitiveHeaders[textproto.CanonicalMIMEHeaderKey(name)] {
+			lines[i] = name + ": " + redactedValue
+		}
+	}
+
+	redacted := []byte(strings.Join(lines, "\r\n"))
+	if body != nil {
+		redacted = append(append(redacted, separator...), redactJSON(body)...)
+	}
+	return redacted
+}
+
+// redactingLogWriter writes the http traces of go-kong to the provider log with their secrets redacted.
+type redactingLogWriter struct{}
+
+func (w redactingLogWriter) Write(p []byte) (int, error) {
+	log.Printf("[DEBUG] Kong Admin API:\n%s", redactHTTPDump(bytes.TrimSpace(p)))
+	return len(p), nil
+}
+
+// resourceSecrets returns the values of the sensitive attributes of a resource, longest first. Kong echoes values back
+// in its errors, unique violations name the conflicting key for example, so they are scrubbed from whatever the
+// provider reports about the resource.
+func resourceSecrets(d *schema.ResourceData, resourceSchema map[string]*schema.Schema) []string {
+	var secrets []string
+	for name, attribute := range resourceSchema {
+		secrets = appendSecrets(secrets, d.Get(name), attribute, attribute.Sensitive || sensitiveFields[name])
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	return secrets
+}
+
+func appendSecrets(secrets []string, value interface{}, attribute *schema.Schema, sensitive bool) []string {
+	switch value := value.(type) {
+	case string:
+		if sensitive && value != "" {
+			secrets = append(secrets, value)
+		}
+	case *schema.Set:
+		secrets = appendSecrets(secrets, value.List(), attribute, sensitive)
+	case []interface{}:
+		for _, element := range value {
+			secrets = appendSecrets(secrets, element, attribute, sensitive)
+		}
+	case map[string]interface{}:
+		block, isBlock := attribute.Elem.(*schema.Resource)
+		for field, fieldValue := range value {
+			if !isBlock {
+				secrets = appendSecrets(secrets, fieldValue, attribute, sensitive)
+			} else if fieldAttribute, ok := block.Schema[field]; ok {
+				secrets = appendSecrets(secrets, fieldValue, fieldAttribute, sensitive || fieldAttribute.Sensitive || sensitiveFields[field])
+			}
+		}
+	}
+	return secrets
+}
+
+func redactSecrets(message string, secrets []string) string {
+	for _, secret := range secrets {
+		message = strings.ReplaceAll(message, secret, redactedValue)
+	}
+	return message
+}
+
+func redactDiagnostics(diags diag.Diagnostics, secrets []string) diag.Diagnostics {
+	for i := range diags {
+		diags[i].Summary = redactSecrets(diags[i].Summary, secrets)
+		diags[i].Detail = redactSecrets(diags[i].Detail, secrets)
+	}
+	return diags
+}
+
+type resourceOperation func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// redactResource scrubs the secrets of a resource from the diagnostics of its operations. A panic is turned into an
+// error diagnostic, re-panicking would have the runtime print the original panic value along with the redacted one.
+func redactResource(resource *schema.Resource) {
+	resourceSchema := resource.Schema
+	redact := func(operation resourceOperation) resourceOperation {
+		if operation == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) (diags diag.Diagnostics) {
+			defer func() {
+				if r := recover(); r != nil {
+					secrets := resourceSecrets(d, resourceSchema)
+					stack := redactSecrets(string(debug.Stack()), secrets)
+					log.Printf("[ERROR] The kong provider panicked: %s\n%s", redactSecrets(fmt.Sprint(r), secrets), stack)
+					diags = diag.Diagnostics{{
+						Severity: diag.Error,
+						Summary:  fmt.Sprintf("The kong provider panicked: %s", redactSecrets(fmt.Sprint(r), secrets)),
+						Detail:   stack,
+					}}
+				}
+			}()
+			return redactDiagnostics(operation(ctx, d, meta), resourceSecrets(d, resourceSchema))
+		}
+	}
+
+	resource.CreateContext = schema.CreateContextFunc(redact(resourceOperation(resource.CreateContext)))
+	resource.ReadContext = schema.ReadContextFunc(redact(resourceOperation(resource.ReadContext)))
+	resource.UpdateContext = schema.UpdateContextFunc(redact(resourceOperation(resource.UpdateContext)))
+	resource.DeleteContext = schema.DeleteContextFunc(redact(resourceOperation(resource.DeleteContext)))
+}
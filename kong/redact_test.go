@@ -0,0 +1,240 @@
+package kong
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestSecretsNeverReachDiagnostics(t *testing.T) {
+	// a four part version is kong enterprise, so no resource is turned away before it talks to kong.
+	server := fakekong.NewServer("3.4.0.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*config).adminClient
+	ctx := context.Background()
+
+	// kong names the conflicting values in unique violations, these credentials make it echo the secrets back.
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("redacted")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.KeyAuths.Create(ctx, consumer.ID, &kong.KeyAuth{Key: kong.String(seededSecret("kong_consumer_key_auth", "key"))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.JWTAuths.Create(ctx, consumer.ID, &kong.JWTAuth{Key: kong.String(seededSecret("kong_consumer_jwt_auth", "key")), Secret: kong.String("another-jwt-secret")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Certificates.Create(ctx, &kong.Certificate{Cert: kong.String("cert"), Key: kong.String("key"), SNIs: kong.StringSlice("taken.example.com")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the attributes a resource needs to reach the part of kong that echoes its secrets.
+	overrides := map[string]map[string]interface{}{
+		"kong_certificate":       {"snis": []interface{}{"taken.example.com"}},
+		"kong_consumer_jwt_auth": {"algorithm": "HS256"},
+		"kong_vault":             {"name": "hcv"},
+	}
+
+	var names []string
+	for name := range p.ResourcesMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resource := p.ResourcesMap[name]
+		configured, computed, secrets := seedResourceSecrets(name, "", resource.Schema, *consumer.ID)
+		for attribute, value := range overrides[name] {
+			configured[attribute] = value
+		}
+		if len(secrets) == 0 {
+			continue
+		}
+
+		created := schema.TestResourceDataRaw(t, resource.Schema, configured)
+		assertNoSecrets(t, name+" create", resource.CreateContext(ctx, created, p.Meta()), secrets)
+
+		// the operations on an existing resource also see the secrets kong computed.
+		for _, operation := range []struct {
+			name string
+			run  func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+		}{
+			{"update", resource.UpdateContext},
+			{"read", resource.ReadContext},
+			{"delete", resource.DeleteContext},
+		} {
+			if operation.run == nil {
+				continue
+			}
+			d := schema.TestResourceDataRaw(t, resource.Schema, configured)
+			for attribute, value := range computed {
+				if err := d.Set(attribute, value); err != nil {
+					t.Fatalf("%s: could not seed %s: %v", name, attribute, err)
+				}
+			}
+			if _, ok := resource.Schema["consumer_id"]; ok {
+				d.SetId(buildConsumerPairID("6e4a2c9a-53a5-4a5a-a6c3-3d1fbbd7e0e7", *consumer.ID))
+			} else {
+				d.SetId("6e4a2c9a-53a5-4a5a-a6c3-3d1fbbd7e0e7")
+			}
+			assertNoSecrets(t, name+" "+operation.name, operation.run(ctx, d, p.Meta()), secrets)
+		}
+	}
+
+	for _, covered := range []string{
+		seededSecret("kong_key", "jwk"),
+		seededSecret("kong_key", "pem.private_key"),
+		seededSecret("kong_vault", "config.hcv.token"),
+		seededSecret("kong_consumer_key_rotation", "active_key"),
+		seededSecret("kong_certificate", "private_key_alt"),
+	} {
+		if !seededSecrets[covered] {
+			t.Errorf("expected %s to be seeded", covered)
+		}
+	}
+}
+
+// seededSecrets are the secrets seedResourceSecrets handed out.
+var seededSecrets = map[string]bool{}
+
+func seededSecret(resource string, path string) string {
+	secret := "leaked-" + resource + "-" + path
+	seededSecrets[secret] = true
+	return secret
+}
+
+// seedResourceSecrets returns a value for every sensitive attribute of a resource schema, and every attribute named
+// like a sensitive field, split into the configured ones and the ones kong computes. Required attributes get a
+// placeholder and consumer ids the given consumer so the requests get as far as kong.
+func seedResourceSecrets(resource string, prefix string, resourceSchema map[string]*schema.Schema, consumerID string) (map[string]interface{}, map[string]interface{}, []string) {
+	configured := map[string]interface{}{}
+	computed := map[string]interface{}{}
+	var secrets []string
+
+	for name, attribute := range resourceSchema {
+		settable := attribute.Required || attribute.Optional
+		sensitive := attribute.Sensitive || sensitiveFields[name]
+
+		var value interface{}
+		switch {
+		case attribute.Type == schema.TypeString && sensitive:
+			secret := seededSecret(resource, prefix+name)
+			secrets = append(secrets, secret)
+			value = secret
+		case attribute.Type == schema.TypeList && sensitive:
+			if _, ok := attribute.Elem.(*schema.Schema); ok {
+				secret := seededSecret(resource, prefix+name)
+				secrets = append(secrets, secret)
+				value = []interface{}{secret}
+			}
+		case attribute.Type == schema.TypeList || attribute.Type == schema.TypeSet:
+			block, ok := attribute.Elem.(*schema.Resource)
+			if !ok {
+				if attribute.Required {
+					value = []interface{}{"redacted"}
+				}
+				break
+			}
+			blockConfigured, blockComputed, blockSecrets := seedResourceSecrets(resource, prefix+name+".", block.Schema, consumerID)
+			for field, fieldValue := range blockComputed {
+				blockConfigured[field] = fieldValue
+			}
+			if len(blockSecrets) > 0 || attribute.Required {
+				secrets = append(secrets, blockSecrets...)
+				value = []interface{}{blockConfigured}
+			}
+		case name == "consumer_id" && settable:
+			value = consumerID
+		case attribute.Required && attribute.Type == schema.TypeString:
+			value = "redacted"
+		case attribute.Required && attribute.Type == schema.TypeInt:
+			value = 1
+		}
+
+		if value == nil {
+			continue
+		}
+		if settable {
+			configured[name] = value
+		} else {
+			computed[name] = value
+		}
+	}
+
+	return configured, computed, secrets
+}
+
+func TestRedactResourcePanics(t *testing.T) {
+	resource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"password": {Type: schema.TypeString, Optional: true},
+		},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			panic("could not use " + d.Get("password").(string))
+		},
+	}
+	redactResource(resource)
+
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{"password": "secret-panic-password"})
+	diags := resource.CreateContext(context.Background(), d, nil)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "could not use "+redactedValue) {
+		t.Errorf("expected the panic as an error diagnostic, got %v", diags)
+	}
+	assertNoSecrets(t, "panicking resource", diags, []string{"secret-panic-password"})
+}
+
+func TestRedactHTTPDump(t *testing.T) {
+	dump := "POST /consumers/bob/basic-auths HTTP/1.1\r\nHost: localhost:8001\r\nAuthorization: Basic c2VjcmV0\r\nKong-Admin-Token: secret-token\r\nContent-Type: application/json\r\n\r\n" +
+		`{"username":"bob","password":"secret-password","tags":["a"]}`
+
+	redacted := string(redactHTTPDump([]byte(dump)))
+	for _, secret := range []string{"c2VjcmV0", "secret-token", "secret-password"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("expected %s to be redacted from %s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{"POST /consumers/bob/basic-auths", "Host: localhost:8001", `"username":"bob"`} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("expected %s to be kept in %s", kept, redacted)
+		}
+	}
+
+	if redacted := string(redactJSON([]byte("password=secret-password"))); strings.Contains(redacted, "secret-password") {
+		t.Errorf("expected a body that is not json to be redacted, got %s", redacted)
+	}
+}
+
+func assertNoSecrets(t *testing.T, name string, diags diag.Diagnostics, secrets []string) {
+	t.Helper()
+	for _, d := range diags {
+		for _, secret := range secrets {
+			if strings.Contains(d.Summary, secret) || strings.Contains(d.Detail, secret) {
+				t.Errorf("%s leaked %s in a diagnostic: %s %s", name, secret, d.Summary, d.Detail)
+			}
+		}
+	}
+}
+
+func TestRedactKongErrorMessages(t *testing.T) {
+	body := `{"code":5,"name":"unique constraint violation","message":"UNIQUE violation detected on '{key=\"secret-key\"}'","fields":{"key":"secret-key"}}`
+	if redacted := string(redactJSON([]byte(body))); strings.Contains(redacted, "secret-key") {
+		t.Errorf("expected the message of an error about a sensitive field to be redacted, got %s", redacted)
+	}
+
+	body = `{"code":5,"name":"unique constraint violation","message":"UNIQUE violation detected on '{name=\"orders\"}'","fields":{"name":"orders"}}`
+	if redacted := string(redactJSON([]byte(body))); !strings.Contains(redacted, `name=\"orders\"`) {
+		t.Errorf("expected the message of an error about other fields to be kept, got %s", redacted)
+	}
+}
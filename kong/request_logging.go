@@ -0,0 +1,91 @@
+package kong
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// EnvProviderLog turns on logging every call to the admin api, its value is the level of the provider logs such as
+// DEBUG or TRACE.
+const EnvProviderLog = "TF_LOG_PROVIDER_KONG"
+
+// kongRequestIDHeader is the id kong gives every admin api request, it finds the request in the logs of kong.
+const kongRequestIDHeader = "X-Kong-Admin-Request-ID"
+
+// LoggingRoundTripper logs the method, path, status, latency and kong request id of every call to the admin api
+// through tflog, with the bodies redacted.
+type LoggingRoundTripper struct {
+	rt        http.RoundTripper
+	workspace string
+}
+
+// NewLoggingRoundTripper returns a round tripper logging the calls it sends through rt, or rt itself when
+// TF_LOG_PROVIDER_KONG is not set.
+func NewLoggingRoundTripper(rt http.RoundTripper, workspace string) http.RoundTripper {
+	if os.Getenv(EnvProviderLog) == "" {
+		return rt
+	}
+	if workspace == "" {
+		workspace = "default"
+	}
+	return &LoggingRoundTripper{rt: rt, workspace: workspace}
+}
+
+// RoundTrip satisfies the RoundTripper interface.
+func (t *LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(req)
+	latency := time.Since(start)
+
+	var responseBody []byte
+	if err == nil {
+		if responseBody, err = ioutil.ReadAll(resp.Body); err != nil {
+			resp.Body.Close()
+			resp = nil
+		} else {
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+		}
+	}
+
+	tflog.Debug(req.Context(), "Kong Admin API request", adminAPILogFields(req, resp, err, latency, t.workspace, requestBody, responseBody)...)
+	return resp, err
+}
+
+// adminAPILogFields are the key value pairs logged for a call to the admin api.
+func adminAPILogFields(req *http.Request, resp *http.Response, err error, latency time.Duration, workspace string, requestBody []byte, responseBody []byte) []interface{} {
+	fields := []interface{}{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"workspace", workspace,
+		"latency_ms", latency.Milliseconds(),
+	}
+	if len(requestBody) > 0 {
+		fields = append(fields, "request_body", string(redactJSON(requestBody)))
+	}
+	if err != nil {
+		return append(fields, "error", err.Error())
+	}
+
+	fields = append(fields, "status", resp.StatusCode)
+	if requestID := resp.Header.Get(kongRequestIDHeader); requestID != "" {
+		fields = append(fields, "kong_request_id", requestID)
+	}
+	if len(responseBody) > 0 {
+		fields = append(fields, "response_body", string(redactJSON(responseBody)))
+	}
+	return fields
+}
@@ -0,0 +1,127 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestAdminAPILogFields(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://localhost:8001/consumers/bob/key-auth", nil)
+	resp := &http.Response{StatusCode: http.StatusConflict, Header: http.Header{}}
+	resp.Header.Set(kongRequestIDHeader, "a-request-id")
+
+	fields := adminAPILogFields(req, resp, nil, 42*time.Millisecond, "default",
+		[]byte(`{"key":"secret-key"}`),
+		[]byte(`{"code":5,"message":"UNIQUE violation detected on '{key=\"secret-key\"}'","fields":{"key":"secret-key"}}`))
+
+	logged := map[string]interface{}{}
+	for i := 0; i < len(fields); i += 2 {
+		logged[fields[i].(string)] = fields[i+1]
+	}
+	expected := map[string]interface{}{
+		"method":          "POST",
+		"path":            "/consumers/bob/key-auth",
+		"workspace":       "default",
+		"latency_ms":      int64(42),
+		"status":          http.StatusConflict,
+		"kong_request_id": "a-request-id",
+	}
+	for key, value := range expected {
+		if logged[key] != value {
+			t.Errorf("expected %s to be logged as %v, got %v", key, value, logged[key])
+		}
+	}
+	for _, body := range []string{"request_body", "response_body"} {
+		if strings.Contains(logged[body].(string), "secret-key") {
+			t.Errorf("expected the secret to be redacted from the %s, got %s", body, logged[body])
+		}
+	}
+}
+
+func TestLoggingRoundTripperIsEnabledByEnv(t *testing.T) {
+	defer os.Setenv(EnvProviderLog, os.Getenv(EnvProviderLog))
+
+	os.Unsetenv(EnvProviderLog)
+	if rt := NewLoggingRoundTripper(http.DefaultTransport, ""); rt != http.DefaultTransport {
+		t.Error("expected no logging without TF_LOG_PROVIDER_KONG")
+	}
+
+	os.Setenv(EnvProviderLog, "DEBUG")
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+	client, err := GetKongClient(Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the provider logger writes to the stderr it finds when it is created.
+	stderr := os.Stderr
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = writer
+	ctx := tfsdklog.NewRootProviderLogger(context.Background(), tfsdklog.WithLevelFromEnv(EnvProviderLog))
+	os.Stderr = stderr
+
+	_, err = client.Services.Create(ctx, &kong.Service{Name: kong.String("logged"), Host: kong.String("example.com")})
+	writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, _ := ioutil.ReadAll(reader)
+	for _, expected := range []string{"Kong Admin API request", `"method":"POST"`, `"path":"/services"`, `"status":201`, `"kong_request_id":`, `"workspace":"default"`} {
+		if !bytes.Contains(output, []byte(expected)) {
+			t.Errorf("expected the log to contain %s, got %s", expected, output)
+		}
+	}
+}
+
+func TestLoggingRoundTripperLogsTheDeclarativePost(t *testing.T) {
+	defer os.Setenv(EnvProviderLog, os.Getenv(EnvProviderLog))
+	os.Setenv(EnvProviderLog, "DEBUG")
+
+	client := newDBLessClient(t, &dbLessKong{})
+
+	stderr := os.Stderr
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = writer
+	ctx := tfsdklog.NewRootProviderLogger(context.Background(), tfsdklog.WithLevelFromEnv(EnvProviderLog))
+	os.Stderr = stderr
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("logged")})
+	if err == nil {
+		_, err = client.KeyAuths.Create(ctx, consumer.ID, &kong.KeyAuth{Key: kong.String("secret-config-key")})
+	}
+	writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, _ := ioutil.ReadAll(reader)
+	for _, expected := range []string{`"method":"POST"`, `"path":"/config"`, `"status":201`} {
+		if !bytes.Contains(output, []byte(expected)) {
+			t.Errorf("expected the log to contain %s, got %s", expected, output)
+		}
+	}
+	if bytes.Contains(output, []byte(`"method":"POST","path":"/consumers"`)) {
+		t.Errorf("expected the writes answered by the declarative layer not to be logged as admin api calls, got %s", output)
+	}
+	if bytes.Contains(output, []byte("secret-config-key")) {
+		t.Errorf("expected the secrets of the posted configuration to be redacted, got %s", output)
+	}
+}
@@ -0,0 +1,51 @@
+package kong
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TimeoutRoundTripper bounds every call to the admin api so a node that stops answering fails the request instead of
+// hanging terraform. GetKongClient puts it closest to the network so time spent waiting for the rate limits does not
+// count against a request.
+type TimeoutRoundTripper struct {
+	rt      http.RoundTripper
+	timeout time.Duration
+}
+
+// NewTimeoutRoundTripper returns a round tripper giving up on requests sent through rt after timeout, or rt itself
+// when timeout is not positive.
+func NewTimeoutRoundTripper(rt http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return rt
+	}
+	return &TimeoutRoundTripper{rt: rt, timeout: timeout}
+}
+
+// RoundTrip satisfies the RoundTripper interface. The deadline of the request context still applies when it is
+// sooner than the timeout.
+func (t *TimeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the timeout covers reading the body too, it is released once the body is closed.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels the context of a request when its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,124 @@
+package kong
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+// hangingServer accepts requests and never answers them until the returned func closes it.
+func hangingServer() (*httptest.Server, func()) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	return server, func() {
+		close(done)
+		server.Close()
+	}
+}
+
+func TestTimeoutRoundTripperGivesUp(t *testing.T) {
+	server, closeServer := hangingServer()
+	defer closeServer()
+
+	client, err := GetKongClient(Config{Address: server.URL, RequestTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := client.Services.Get(context.Background(), kong.String("hanging")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the request to give up after 50ms, took %v", elapsed)
+	}
+
+	if rt := NewTimeoutRoundTripper(http.DefaultTransport, 0); rt != http.DefaultTransport {
+		t.Error("expected no timeout when none is set")
+	}
+}
+
+func TestResourceOperationsStopAtTheContextDeadline(t *testing.T) {
+	server, closeServer := hangingServer()
+	defer closeServer()
+
+	client, err := GetKongClient(Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &config{adminClient: client}
+
+	tests := map[string]map[string]interface{}{
+		"kong_service":  {"name": "hanging", "protocol": "http", "host": "example.com"},
+		"kong_consumer": {"username": "hanging"},
+		"kong_upstream": {"name": "hanging"},
+	}
+	for name, raw := range tests {
+		resource := Provider().ResourcesMap[name]
+		d := schema.TestResourceDataRaw(t, resource.Schema, raw)
+
+		// the sdk gives create the deadline of its timeout, without a request_timeout only that deadline stops the call.
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		diags := resource.CreateContext(ctx, d, meta)
+		cancel()
+
+		if !diags.HasError() {
+			t.Errorf("expected creating %s against a hanging admin api to fail", name)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected creating %s to stop at the deadline, took %v", name, elapsed)
+		}
+	}
+}
+
+func TestResourcesHaveTimeouts(t *testing.T) {
+	for name, resource := range Provider().ResourcesMap {
+		timeouts := resource.Timeouts
+		if timeouts == nil || timeouts.Create == nil || timeouts.Read == nil || timeouts.Delete == nil {
+			t.Errorf("expected %s to have create, read and delete timeouts", name)
+			continue
+		}
+		if (timeouts.Update != nil) != (resource.UpdateContext != nil) {
+			t.Errorf("expected %s to have an update timeout only when it can be updated", name)
+		}
+	}
+}
+
+func TestProviderConfiguresRequestTimeout(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	for _, requestTimeout := range []string{"0", "30s"} {
+		p := Provider()
+		raw := map[string]interface{}{"kong_admin_uri": server.URL, "request_timeout": requestTimeout}
+		if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if _, err := p.Meta().(*config).adminClient.Services.ListAll(context.Background()); err != nil {
+			t.Errorf("expected the client configured with a request_timeout of %s to work, got %v", requestTimeout, err)
+		}
+	}
+
+	if os.Getenv("KONG_REQUEST_TIMEOUT") == "" {
+		if value, err := Provider().Schema["request_timeout"].DefaultValue(); err != nil || value != "0" {
+			t.Errorf("expected requests to have no time limit by default, got %v %v", value, err)
+		}
+	}
+
+	raw := map[string]interface{}{"kong_admin_uri": server.URL, "request_timeout": "soon"}
+	if diags := Provider().Validate(terraform.NewResourceConfigRaw(raw)); !diags.HasError() {
+		t.Error("expected a request_timeout that is not a duration to be rejected")
+	}
+}
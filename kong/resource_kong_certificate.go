@@ -2,8 +2,11 @@ package kong
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/kong/go-kong/kong"
@@ -15,9 +18,16 @@ func resourceKongCertificate() *schema.Resource {
 		ReadContext:   resourceKongCertificateRead,
 		DeleteContext: resourceKongCertificateDelete,
 		UpdateContext: resourceKongCertificateUpdate,
+		CustomizeDiff: resourceKongCertificateCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"certificate": &schema.Schema{
@@ -27,10 +37,24 @@ func resourceKongCertificate() *schema.Resource {
 				Sensitive: false,
 			},
 			"private_key": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         false,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressHashedSecretDiff,
+			},
+			"certificate_alt": &schema.Schema{
 				Type:      schema.TypeString,
 				Optional:  true,
 				ForceNew:  false,
-				Sensitive: true,
+				Sensitive: false,
+			},
+			"private_key_alt": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         false,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressHashedSecretDiff,
 			},
 			"snis": &schema.Schema{
 				Type:     schema.TypeList,
@@ -44,18 +68,104 @@ func resourceKongCertificate() *schema.Resource {
 				ForceNew: false,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"validate_certificate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    false,
+				Default:     false,
+				Description: "Reject at plan time a certificate that has expired, does not match private_key or whose SANs do not cover snis",
+			},
+			"not_before": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"not_after": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dns_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"serial_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"fingerprint_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
 
+func resourceKongCertificateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("certificate") {
+		for _, key := range certificateMetadataProperties {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	certificatePEM := d.Get("certificate").(string)
+
+	if d.HasChange("certificate") {
+		cert, err := parseCertificatePEM(certificatePEM)
+		if err != nil {
+			cert = nil
+		}
+		for key, value := range flattenCertificateMetadata(cert) {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !d.Get("validate_certificate").(bool) || !d.NewValueKnown("private_key") || !d.NewValueKnown("snis") {
+		return nil
+	}
+
+	var snis []string
+	for _, sni := range d.Get("snis").([]interface{}) {
+		snis = append(snis, sni.(string))
+	}
+
+	if err := validateCertificate(certificatePEM, unhashedSecret(d.Get("private_key").(string)), snis, time.Now()); err != nil {
+		return err
+	}
+
+	if !d.NewValueKnown("certificate_alt") || !d.NewValueKnown("private_key_alt") {
+		return nil
+	}
+	if certificateAltPEM := d.Get("certificate_alt").(string); certificateAltPEM != "" {
+		if err := validateCertificate(certificateAltPEM, unhashedSecret(d.Get("private_key_alt").(string)), snis, time.Now()); err != nil {
+			return fmt.Errorf("certificate_alt: %v", err)
+		}
+	}
+
+	return nil
+}
+
 func resourceKongCertificateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 
 	certificateRequest := buildCertificateRequestFromResourceData(d)
 	client := meta.(*config).adminClient.Certificates
 	certificate, err := client.Create(ctx, certificateRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong certificate: %v error: %v", certificateRequest, err))
+		return kongErrors.diagnostics("failed to create kong certificate", resourceKongCertificate().Schema, fmt.Errorf("failed to create kong certificate: %s error: %w", redactedRequest(certificateRequest), err))
 	}
 
 	d.SetId(*certificate.ID)
@@ -64,28 +174,55 @@ func resourceKongCertificateCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceKongCertificateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
 	certificateRequest := buildCertificateRequestFromResourceData(d)
 	certificateRequest.ID = kong.String(d.Id())
 
-	client := meta.(*config).adminClient.Certificates
-	_, err := client.Update(ctx, certificateRequest)
+	client := meta.(*config).adminClient
+	var err error
+	if d.HasChange("certificate_alt") && certificateRequest.CertAlt == nil {
+		err = clearAlternateCertificate(ctx, client, certificateRequest)
+	} else {
+		_, err = client.Certificates.Update(ctx, certificateRequest)
+	}
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong certificate: %s", err))
+		return kongErrors.diagnostics("error updating kong certificate", resourceKongCertificate().Schema, fmt.Errorf("error updating kong certificate: %w", err))
 	}
 
 	return resourceKongCertificateRead(ctx, d, meta)
 }
 
+// clearAlternateCertificate patches the certificate with explicit nulls for cert_alt and key_alt, a PATCH omitting
+// them keeps the alternate pair. Unlike a PUT it keeps the private key kong has when only its hash is in state.
+func clearAlternateCertificate(ctx context.Context, client *kong.Client, certificateRequest *kong.Certificate) error {
+	certificateRequest.CertAlt = nil
+	certificateRequest.KeyAlt = nil
+	body, err := json.Marshal(certificateRequest)
+	if err != nil {
+		return err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return err
+	}
+	fields["cert_alt"] = nil
+	fields["key_alt"] = nil
+
+	return doKongRequest(ctx, client, "PATCH", fmt.Sprintf("/certificates/%s", *certificateRequest.ID), fields, nil)
+}
+
 func buildCertificateRequestFromResourceData(d *schema.ResourceData) *kong.Certificate {
 	certificateRequest := &kong.Certificate{
 		Cert: kong.String(d.Get("certificate").(string)),
-		Key:  kong.String(d.Get("private_key").(string)),
+		Key:  readSecretWithEmptyPtrFromResource(d, "private_key"),
 		SNIs: readStringArrayPtrFromResource(d, "snis"),
 		Tags: readStringArrayPtrFromResource(d, "tags"),
 	}
+	certificateRequest.CertAlt = readStringPtrFromResource(d, "certificate_alt")
+	certificateRequest.KeyAlt = readSecretPtrFromResource(d, "private_key_alt")
 	return certificateRequest
 }
 
@@ -108,15 +245,34 @@ func resourceKongCertificateRead(ctx context.Context, d *schema.ResourceData, me
 			if err != nil {
 				return diag.FromErr(err)
 			}
+
+			cert, err := parseCertificatePEM(*certificate.Cert)
+			if err != nil && !isVaultReference(*certificate.Cert) {
+				log.Printf("[WARN] Could not parse kong certificate %s: %v", d.Id(), err)
+			}
+			for key, value := range flattenCertificateMetadata(cert) {
+				if err := d.Set(key, value); err != nil {
+					return diag.FromErr(err)
+				}
+			}
 		}
 
 		if certificate.Key != nil {
-			err := d.Set("private_key", &certificate.Key)
+			err := d.Set("private_key", secretStateValue(meta, certificate.Key))
 			if err != nil {
 				return diag.FromErr(err)
 			}
 		}
 
+		err = d.Set("certificate_alt", certificate.CertAlt)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("private_key_alt", secretStateValue(meta, certificate.KeyAlt))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
 		if certificate.SNIs != nil {
 			err := d.Set("snis", StringValueSlice(certificate.SNIs))
 			if err != nil {
@@ -127,6 +283,11 @@ func resourceKongCertificateRead(ctx context.Context, d *schema.ResourceData, me
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		// validate_certificate only lives in terraform, make sure an imported certificate gets the default
+		err = d.Set("validate_certificate", d.Get("validate_certificate").(bool))
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return diags
@@ -18,6 +18,12 @@ func resourceKongConsumer() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"username": {
@@ -41,6 +47,7 @@ func resourceKongConsumer() *schema.Resource {
 }
 
 func resourceKongConsumerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 
 	consumerRequest := &kong.Consumer{
 		Username: readStringPtrFromResource(d, "username"),
@@ -52,7 +59,7 @@ func resourceKongConsumerCreate(ctx context.Context, d *schema.ResourceData, met
 	consumer, err := client.Create(ctx, consumerRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong consumer: %v error: %v", consumerRequest, err))
+		return kongErrors.diagnostics("failed to create kong consumer", resourceKongConsumer().Schema, fmt.Errorf("failed to create kong consumer: %s error: %w", redactedRequest(consumerRequest), err))
 	}
 
 	d.SetId(*consumer.ID)
@@ -61,6 +68,7 @@ func resourceKongConsumerCreate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceKongConsumerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
 	consumerRequest := &kong.Consumer{
@@ -74,7 +82,7 @@ func resourceKongConsumerUpdate(ctx context.Context, d *schema.ResourceData, met
 	_, err := client.Update(ctx, consumerRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong consumer: %s", err))
+		return kongErrors.diagnostics("error updating kong consumer", resourceKongConsumer().Schema, fmt.Errorf("error updating kong consumer: %w", err))
 	}
 
 	return resourceKongConsumerRead(ctx, d, meta)
@@ -17,6 +17,12 @@ func resourceKongConsumerACL() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"consumer_id": {
 				Type:     schema.TypeString,
@@ -39,6 +45,7 @@ func resourceKongConsumerACL() *schema.Resource {
 }
 
 func resourceKongConsumerACLCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	ACLGroupRequest := &kong.ACLGroup{
 		Group: kong.String(d.Get("group").(string)),
 		Tags:  readStringArrayPtrFromResource(d, "tags"),
@@ -50,7 +57,7 @@ func resourceKongConsumerACLCreate(ctx context.Context, d *schema.ResourceData,
 	aclGroup, err := client.Create(ctx, consumerId, ACLGroupRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong ACL Group: %v error: %v", ACLGroupRequest, err))
+		return kongErrors.diagnostics("failed to create kong ACL Group", resourceKongConsumerACL().Schema, fmt.Errorf("failed to create kong ACL Group: %s error: %w", redactedRequest(ACLGroupRequest), err))
 	}
 
 	d.SetId(buildConsumerPairID(*aclGroup.ID, *consumerId))
@@ -59,6 +66,7 @@ func resourceKongConsumerACLCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceKongConsumerACLUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	id, err := splitConsumerID(d.Id())
 
 	ACLGroupRequest := &kong.ACLGroup{
@@ -73,7 +81,7 @@ func resourceKongConsumerACLUpdate(ctx context.Context, d *schema.ResourceData,
 	_, err = client.Update(ctx, consumerId, ACLGroupRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong ACL Group: %s", err))
+		return kongErrors.diagnostics("error updating kong ACL Group", resourceKongConsumerACL().Schema, fmt.Errorf("error updating kong ACL Group: %w", err))
 	}
 
 	return resourceKongConsumerACLRead(ctx, d, meta)
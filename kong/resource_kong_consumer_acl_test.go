@@ -113,7 +113,7 @@ func testAccCheckConsumerACLExists(resourceKey string) resource.TestCheckFunc {
 
 const testCreateConsumerACLConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -134,7 +134,7 @@ resource "kong_consumer_acl" "consumer_acl" {
 `
 const testUpdateConsumerACLConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -0,0 +1,234 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func resourceKongConsumerACLs() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongConsumerACLsCreate,
+		ReadContext:   resourceKongConsumerACLsRead,
+		DeleteContext: resourceKongConsumerACLsDelete,
+		UpdateContext: resourceKongConsumerACLsUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"consumer_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"groups": {
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ignore_external_groups": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: false,
+				Default:  false,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKongConsumerACLsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	consumerId := kong.String(d.Get("consumer_id").(string))
+
+	if err := reconcileConsumerACLGroups(ctx, d, meta, nil, readStringArrayFromSet(d.Get("groups").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*consumerId)
+
+	return resourceKongConsumerACLsRead(ctx, d, meta)
+}
+
+func resourceKongConsumerACLsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	old, new := d.GetChange("groups")
+
+	err := reconcileConsumerACLGroups(ctx, d, meta, readStringArrayFromSet(old.(*schema.Set)), readStringArrayFromSet(new.(*schema.Set)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceKongConsumerACLsRead(ctx, d, meta)
+}
+
+func resourceKongConsumerACLsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	existing, err := listConsumerACLGroups(ctx, meta, kong.String(d.Id()))
+
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not list kong ACL Groups for consumer: %s error: %v", d.Id(), err))
+	}
+
+	managed := readStringArrayFromSet(d.Get("groups").(*schema.Set))
+	ignoreExternal := d.Get("ignore_external_groups").(bool)
+
+	var groups []string
+	for group := range existing {
+		if !ignoreExternal || contains(managed, group) {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+
+	// the tags of a group that differ from the ones in state are reported so the next apply sets them on every group.
+	tags := StringValueSlice(readStringArrayPtrFromResource(d, "tags"))
+	for _, group := range groups {
+		if groupTags := StringValueSlice(existing[group].Tags); !reflect.DeepEqual(groupTags, tags) {
+			tags = groupTags
+			break
+		}
+	}
+
+	err = d.Set("consumer_id", d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("groups", groups)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("ignore_external_groups", ignoreExternal)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("tags", tags)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceKongConsumerACLsDelete only removes the groups in state, groups managed elsewhere, such as by a
+// kong_consumer_acl, are left alone whether or not external groups are ignored.
+func resourceKongConsumerACLsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	consumerId := kong.String(d.Get("consumer_id").(string))
+	client := meta.(*config).adminClient.ACLs
+
+	existing, err := listConsumerACLGroups(ctx, meta, consumerId)
+	if kong.IsNotFoundErr(err) {
+		return diags
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not list kong ACL Groups for consumer: %s error: %v", *consumerId, err))
+	}
+
+	for _, group := range readStringArrayFromSet(d.Get("groups").(*schema.Set)) {
+		if existing[group] == nil {
+			continue
+		}
+		err := client.Delete(ctx, consumerId, existing[group].ID)
+		if err != nil && !kong.IsNotFoundErr(err) {
+			return diag.FromErr(fmt.Errorf("could not delete kong ACL Group: %s error: %v", group, err))
+		}
+	}
+
+	return diags
+}
+
+// reconcileConsumerACLGroups moves the consumer from the old groups to the new ones. Unless external groups are
+// ignored every group in kong counts as an old one. Groups that already exist in kong are not created again and when
+// tags change they are updated on every remaining group.
+func reconcileConsumerACLGroups(ctx context.Context, d *schema.ResourceData, meta interface{}, old []string, new []string) error {
+	consumerId := kong.String(d.Get("consumer_id").(string))
+	client := meta.(*config).adminClient.ACLs
+
+	existing, err := listConsumerACLGroups(ctx, meta, consumerId)
+	if err != nil {
+		return fmt.Errorf("could not list kong ACL Groups for consumer: %s error: %v", *consumerId, err)
+	}
+
+	if !d.Get("ignore_external_groups").(bool) {
+		for group := range existing {
+			if !contains(old, group) {
+				old = append(old, group)
+			}
+		}
+	}
+
+	for _, group := range old {
+		if contains(new, group) || existing[group] == nil {
+			continue
+		}
+		err := client.Delete(ctx, consumerId, existing[group].ID)
+		if err != nil && !kong.IsNotFoundErr(err) {
+			return fmt.Errorf("could not delete kong ACL Group: %s error: %v", group, err)
+		}
+	}
+
+	tags := readStringArrayPtrFromResource(d, "tags")
+	for _, group := range new {
+		if existing[group] == nil {
+			ACLGroupRequest := &kong.ACLGroup{
+				Group: kong.String(group),
+				Tags:  tags,
+			}
+			if _, err := client.Create(ctx, consumerId, ACLGroupRequest); err != nil {
+				return fmt.Errorf("failed to create kong ACL Group: %s error: %v", redactedRequest(ACLGroupRequest), err)
+			}
+		} else if d.HasChange("tags") {
+			ACLGroupRequest := &kong.ACLGroup{
+				ID:    existing[group].ID,
+				Group: kong.String(group),
+				Tags:  tags,
+			}
+			if _, err := client.Update(ctx, consumerId, ACLGroupRequest); err != nil {
+				return fmt.Errorf("error updating kong ACL Group: %s error: %v", group, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func listConsumerACLGroups(ctx context.Context, meta interface{}, consumerId *string) (map[string]*kong.ACLGroup, error) {
+	client := meta.(*config).adminClient.ACLs
+	groups := map[string]*kong.ACLGroup{}
+
+	opt := &kong.ListOpt{Size: 1000}
+	for opt != nil {
+		data, next, err := client.ListForConsumer(ctx, consumerId, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, aclGroup := range data {
+			if aclGroup.Group != nil {
+				groups[*aclGroup.Group] = aclGroup
+			}
+		}
+		opt = next
+	}
+
+	return groups, nil
+}
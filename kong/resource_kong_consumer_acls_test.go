@@ -0,0 +1,223 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestAccConsumerACLs(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsumerACLsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConsumerACLsConfig, `"group1", "group2"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerACLsGroups("kong_consumer_acls.consumer_acls", "group1", "group2"),
+					resource.TestCheckResourceAttr("kong_consumer_acls.consumer_acls", "groups.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerACLsConfig, `"group2", "group3"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerACLsGroups("kong_consumer_acls.consumer_acls", "group2", "group3"),
+					resource.TestCheckResourceAttr("kong_consumer_acls.consumer_acls", "groups.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "kong_consumer_acls.consumer_acls",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccConsumerACLsExternalGroups(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsumerACLsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConsumerACLsConfig, `"group1"`, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerACLsGroups("kong_consumer_acls.consumer_acls", "group1"),
+					testAccAddConsumerACLGroup("kong_consumer_acls.consumer_acls", "external"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerACLsConfig, `"group1", "group2"`, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerACLsGroups("kong_consumer_acls.consumer_acls", "external", "group1", "group2"),
+					resource.TestCheckResourceAttr("kong_consumer_acls.consumer_acls", "groups.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerACLsConfig, `"group1", "group2"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerACLsGroups("kong_consumer_acls.consumer_acls", "group1", "group2"),
+				),
+			},
+		},
+	})
+}
+
+func TestConsumerACLsDeleteOnlyRemovesItsGroups(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*config).adminClient
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("acls")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aclsResource := p.ResourcesMap["kong_consumer_acls"]
+	d := schema.TestResourceDataRaw(t, aclsResource.Schema, map[string]interface{}{
+		"consumer_id": *consumer.ID,
+		"groups":      []interface{}{"a", "b"},
+		"tags":        []interface{}{"managed"},
+	})
+	if diags := aclsResource.CreateContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// a group added by a kong_consumer_acl after the consumer acls were created, and tags changed outside terraform.
+	if _, err := client.ACLs.Create(ctx, consumer.ID, &kong.ACLGroup{Group: kong.String("separate")}); err != nil {
+		t.Fatal(err)
+	}
+	groupA, err := client.ACLs.Get(ctx, consumer.ID, kong.String("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ACLs.Update(ctx, consumer.ID, &kong.ACLGroup{ID: groupA.ID, Group: groupA.Group, Tags: kong.StringSlice("changed")}); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := aclsResource.Data(&terraform.InstanceState{ID: *consumer.ID})
+	if diags := aclsResource.ReadContext(ctx, imported, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if tags := imported.Get("tags").([]interface{}); len(tags) != 1 || tags[0] != "changed" {
+		t.Errorf("expected the tags changed outside terraform to be read, got %v", tags)
+	}
+
+	if diags := aclsResource.DeleteContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	groups, _, err := client.ACLs.ListForConsumer(ctx, consumer.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || *groups[0].Group != "separate" {
+		t.Errorf("expected only the group managed elsewhere to be left, got %d groups", len(groups))
+	}
+}
+
+func testAccCheckConsumerACLsDestroy(state *terraform.State) error {
+
+	client := testAccProvider.Meta().(*config).adminClient.ACLs
+
+	for _, rs := range getResourcesByType("kong_consumer_acls", state) {
+		aclGroups, _, err := client.ListForConsumer(context.Background(), kong.String(rs.Primary.ID), nil)
+
+		if !kong.IsNotFoundErr(err) && err != nil {
+			return fmt.Errorf("error calling list consumer acl groups: %v", err)
+		}
+
+		if len(aclGroups) > 0 {
+			return fmt.Errorf("acl groups for consumer %s still exist, %+v", rs.Primary.ID, aclGroups)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckConsumerACLsGroups(resourceKey string, expected ...string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*config).adminClient.ACLs
+		aclGroups, _, err := client.ListForConsumer(context.Background(), kong.String(rs.Primary.ID), nil)
+		if err != nil {
+			return err
+		}
+
+		var groups []string
+		for _, aclGroup := range aclGroups {
+			groups = append(groups, *aclGroup.Group)
+		}
+		sort.Strings(groups)
+
+		if strings.Join(groups, ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("expected consumer %s to have groups %v found %v", rs.Primary.ID, expected, groups)
+		}
+
+		return nil
+	}
+}
+
+func testAccAddConsumerACLGroup(resourceKey string, group string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		client := testAccProvider.Meta().(*config).adminClient.ACLs
+		_, err := client.Create(context.Background(), kong.String(rs.Primary.ID), &kong.ACLGroup{Group: kong.String(group)})
+
+		return err
+	}
+}
+
+const testConsumerACLsConfig = `
+resource "kong_consumer" "my_consumer" {
+	username  = "tf-acc-User1"
+	custom_id = "123"
+}
+
+resource "kong_plugin" "acl_plugin" {
+	name        = "acl"
+	config_json = <<EOT
+	{
+		"allow": ["group1", "group2"]
+	}
+EOT
+}
+
+resource "kong_consumer_acls" "consumer_acls" {
+	consumer_id            = "${kong_consumer.my_consumer.id}"
+	groups                 = [%s]
+	ignore_external_groups = %t
+	tags                   = ["myTag"]
+}
+`
@@ -2,7 +2,10 @@ package kong
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/kong/go-kong/kong"
@@ -14,6 +17,15 @@ func resourceKongConsumerBasicAuth() *schema.Resource {
 		ReadContext:   resourceKongConsumerBasicAuthRead,
 		DeleteContext: resourceKongConsumerBasicAuthDelete,
 		UpdateContext: resourceKongConsumerBasicAuthUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"consumer_id": {
 				Type:     schema.TypeString,
@@ -26,9 +38,10 @@ func resourceKongConsumerBasicAuth() *schema.Resource {
 				ForceNew: false,
 			},
 			"password": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: false,
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         false,
+				DiffSuppressFunc: suppressHashedSecretDiff,
 			},
 			"tags": {
 				Type:     schema.TypeList,
@@ -41,9 +54,10 @@ func resourceKongConsumerBasicAuth() *schema.Resource {
 }
 
 func resourceKongConsumerBasicAuthCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	BasicAuthRequest := &kong.BasicAuth{
 		Username: kong.String(d.Get("username").(string)),
-		Password: kong.String(d.Get("password").(string)),
+		Password: readSecretWithEmptyPtrFromResource(d, "password"),
 		Tags:     readStringArrayPtrFromResource(d, "tags"),
 	}
 
@@ -53,21 +67,27 @@ func resourceKongConsumerBasicAuthCreate(ctx context.Context, d *schema.Resource
 	basicAuth, err := client.Create(ctx, consumerId, BasicAuthRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong basic auth: %v error: %v", BasicAuthRequest, err))
+		return kongErrors.diagnostics("failed to create kong basic auth", resourceKongConsumerBasicAuth().Schema, fmt.Errorf("failed to create kong basic auth: %s error: %w", redactedRequest(BasicAuthRequest), err))
 	}
 
 	d.SetId(buildConsumerPairID(*basicAuth.ID, *consumerId))
 
+	// kong only returns a hash of the password, the configured value is kept in state and checked against it by the read
+	if err := d.Set("password", secretStateValue(meta, BasicAuthRequest.Password)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceKongConsumerBasicAuthRead(ctx, d, meta)
 }
 
 func resourceKongConsumerBasicAuthUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	id, err := splitConsumerID(d.Id())
 
 	BasicAuthRequest := &kong.BasicAuth{
 		ID:       kong.String(id.ID),
 		Username: kong.String(d.Get("username").(string)),
-		Password: kong.String(d.Get("password").(string)),
+		Password: readSecretWithEmptyPtrFromResource(d, "password"),
 		Tags:     readStringArrayPtrFromResource(d, "tags"),
 	}
 
@@ -77,7 +97,13 @@ func resourceKongConsumerBasicAuthUpdate(ctx context.Context, d *schema.Resource
 	_, err = client.Update(ctx, consumerId, BasicAuthRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong basic auth: %s", err))
+		return kongErrors.diagnostics("error updating kong basic auth", resourceKongConsumerBasicAuth().Schema, fmt.Errorf("error updating kong basic auth: %w", err))
+	}
+
+	if BasicAuthRequest.Password != nil {
+		if err := d.Set("password", secretStateValue(meta, BasicAuthRequest.Password)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourceKongConsumerBasicAuthRead(ctx, d, meta)
@@ -114,6 +140,13 @@ func resourceKongConsumerBasicAuthRead(ctx context.Context, d *schema.ResourceDa
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		if password := d.Get("password").(string); password == "" || basicAuthPasswordDrifted(password, basicAuth) {
+			// the password was imported or changed outside terraform, storing kong's hash makes the plan set it back
+			err = d.Set("password", basicAuth.Password)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
 	}
 
 	return diags
@@ -134,3 +167,20 @@ func resourceKongConsumerBasicAuthDelete(ctx context.Context, d *schema.Resource
 
 	return diags
 }
+
+// hashBasicAuthPassword hashes a password the way the basic-auth plugin does, a SHA-1 of the password salted
+// with the consumer id.
+func hashBasicAuthPassword(consumerID string, password string) string {
+	sum := sha1.Sum([]byte(password + consumerID))
+	return hex.EncodeToString(sum[:])
+}
+
+// basicAuthPasswordDrifted reports whether the hash stored by kong no longer matches the password held in state.
+// Nothing can be compared when state only holds a write_only_secrets hash.
+func basicAuthPasswordDrifted(password string, basicAuth *kong.BasicAuth) bool {
+	if password == "" || isHashedSecret(password) || basicAuth.Password == nil || basicAuth.Consumer == nil ||
+		basicAuth.Consumer.ID == nil {
+		return false
+	}
+	return hashBasicAuthPassword(*basicAuth.Consumer.ID, password) != *basicAuth.Password
+}
@@ -8,9 +8,12 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/containers"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
 )
 
 func TestAccConsumerBasicAuth(t *testing.T) {
+	skipInDatabase(t, "kong only returns basic auth passwords hashed, db_less mode refuses them", containers.DatabaseOff)
 
 	resource.Test(t, resource.TestCase{
 		Providers:    testAccProviders,
@@ -35,10 +38,166 @@ func TestAccConsumerBasicAuth(t *testing.T) {
 					resource.TestCheckResourceAttr("kong_consumer_basic_auth.consumer_basic_auth", "tags.1", "anotherTag"),
 				),
 			},
+			{
+				ResourceName:      "kong_consumer_basic_auth.consumer_basic_auth",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// kong only returns the hash of the password
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func TestAccConsumerBasicAuthPasswordDrift(t *testing.T) {
+	skipInDatabase(t, "kong only returns basic auth passwords hashed, db_less mode refuses them", containers.DatabaseOff)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsumerBasicAuthDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testCreateConsumerBasicAuthConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerBasicAuthExists("kong_consumer_basic_auth.consumer_basic_auth"),
+					resource.TestCheckResourceAttr("kong_consumer_basic_auth.consumer_basic_auth", "password", "bar"),
+				),
+			},
+			{
+				Config:             testCreateConsumerBasicAuthConfig,
+				Check:              testAccChangeConsumerBasicAuthPassword("kong_consumer_basic_auth.consumer_basic_auth", "changed"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testCreateConsumerBasicAuthConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("kong_consumer_basic_auth.consumer_basic_auth", "password", "bar"),
+				),
+			},
 		},
 	})
 }
 
+func TestHashBasicAuthPassword(t *testing.T) {
+	consumerID := "4e8c2e9c-5e9b-4c1e-9d1d-2a8b3a0d6f7e"
+	hash := "497f442c39a2cfa4e2835a2aee58f7a074665dcc"
+
+	if got := hashBasicAuthPassword(consumerID, "bar"); got != hash {
+		t.Errorf("expected hash %s got %s", hash, got)
+	}
+
+	basicAuth := &kong.BasicAuth{
+		Consumer: &kong.Consumer{ID: kong.String(consumerID)},
+		Password: kong.String(hash),
+	}
+	if basicAuthPasswordDrifted("bar", basicAuth) {
+		t.Error("matching password should not be reported as drifted")
+	}
+	if !basicAuthPasswordDrifted("changed", basicAuth) {
+		t.Error("changed password should be reported as drifted")
+	}
+	if basicAuthPasswordDrifted(hashSecret("changed"), basicAuth) {
+		t.Error("write only secret hash can not be compared and should not be reported as drifted")
+	}
+}
+
+func TestConsumerBasicAuthImportStoresKongHash(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*config).adminClient
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("imported")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	basicAuth, err := client.BasicAuths.Create(ctx, consumer.ID, &kong.BasicAuth{Username: kong.String("imported"), Password: kong.String("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basicAuthResource := p.ResourcesMap["kong_consumer_basic_auth"]
+	imported, err := basicAuthResource.Importer.State(basicAuthResource.Data(&terraform.InstanceState{ID: buildConsumerPairID(*basicAuth.ID, *consumer.ID)}), p.Meta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := imported[0]
+	if diags := basicAuthResource.ReadContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	expected := map[string]string{
+		"consumer_id": *consumer.ID,
+		"username":    "imported",
+		"password":    hashBasicAuthPassword(*consumer.ID, "secret"),
+	}
+	for key, value := range expected {
+		if d.Get(key) != value {
+			t.Errorf("expected the import to set %s to %s, got %v", key, value, d.Get(key))
+		}
+	}
+}
+
+func TestConsumerBasicAuthReadStoresKongHashWithoutPassword(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*config).adminClient
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("unknown-password")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	basicAuth, err := client.BasicAuths.Create(ctx, consumer.ID, &kong.BasicAuth{Username: kong.String("unknown-password"), Password: kong.String("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a state without a password, as left by an import, has nothing to compare kong's hash with.
+	basicAuthResource := p.ResourcesMap["kong_consumer_basic_auth"]
+	d := basicAuthResource.Data(&terraform.InstanceState{ID: buildConsumerPairID(*basicAuth.ID, *consumer.ID)})
+	if diags := basicAuthResource.ReadContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if password := d.Get("password"); password != hashBasicAuthPassword(*consumer.ID, "secret") {
+		t.Errorf("expected the read to store kong's hash of the password, got %v", password)
+	}
+}
+
+func testAccChangeConsumerBasicAuthPassword(resourceKey string, password string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		client := testAccProvider.Meta().(*config).adminClient.BasicAuths
+		id, err := splitConsumerID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Update(context.Background(), kong.String(id.ConsumerID), &kong.BasicAuth{
+			ID:       kong.String(id.ID),
+			Password: kong.String(password),
+		})
+
+		return err
+	}
+}
+
 func testAccCheckConsumerBasicAuthDestroy(state *terraform.State) error {
 
 	client := testAccProvider.Meta().(*config).adminClient.BasicAuths
@@ -95,7 +254,7 @@ func testAccCheckConsumerBasicAuthExists(resourceKey string) resource.TestCheckF
 
 const testCreateConsumerBasicAuthConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -112,7 +271,7 @@ resource "kong_consumer_basic_auth" "consumer_basic_auth" {
 `
 const testUpdateConsumerBasicAuthConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
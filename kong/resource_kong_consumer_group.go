@@ -0,0 +1,129 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func resourceKongConsumerGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongConsumerGroupCreate,
+		ReadContext:   resourceKongConsumerGroupRead,
+		DeleteContext: resourceKongConsumerGroupDelete,
+		UpdateContext: resourceKongConsumerGroupUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: false,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKongConsumerGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_consumer_group", "", true); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	consumerGroupRequest := &kongConsumerGroup{
+		Name: readStringPtrFromResource(d, "name"),
+		Tags: readStringArrayPtrFromResource(d, "tags"),
+	}
+
+	client := meta.(*config).adminClient
+	consumerGroup, err := createKongConsumerGroup(ctx, client, consumerGroupRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("failed to create kong consumer group", resourceKongConsumerGroup().Schema, fmt.Errorf("failed to create kong consumer group: %s error: %w", redactedRequest(consumerGroupRequest), err))
+	}
+
+	d.SetId(*consumerGroup.ID)
+
+	return resourceKongConsumerGroupRead(ctx, d, meta)
+}
+
+func resourceKongConsumerGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_consumer_group", "", true); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	consumerGroupRequest := &kongConsumerGroup{
+		ID:   kong.String(d.Id()),
+		Name: readStringPtrFromResource(d, "name"),
+		Tags: readStringArrayPtrFromResource(d, "tags"),
+	}
+
+	client := meta.(*config).adminClient
+	_, err := updateKongConsumerGroup(ctx, client, consumerGroupRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("error updating kong consumer group", resourceKongConsumerGroup().Schema, fmt.Errorf("error updating kong consumer group: %w", err))
+	}
+
+	return resourceKongConsumerGroupRead(ctx, d, meta)
+}
+
+func resourceKongConsumerGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*config).adminClient
+	consumerGroup, err := getKongConsumerGroup(ctx, client, d.Id())
+
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not find kong consumer group: %v", err))
+	}
+
+	if consumerGroup == nil || consumerGroup.ConsumerGroup == nil {
+		d.SetId("")
+	} else {
+		err = d.Set("name", consumerGroup.ConsumerGroup.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("tags", consumerGroup.ConsumerGroup.Tags)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func resourceKongConsumerGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*config).adminClient
+	err := deleteKongConsumerGroup(ctx, client, d.Id())
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("could not delete kong consumer group: %v", err))
+	}
+
+	return diags
+}
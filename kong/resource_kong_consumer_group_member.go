@@ -0,0 +1,116 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func resourceKongConsumerGroupMember() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongConsumerGroupMemberCreate,
+		ReadContext:   resourceKongConsumerGroupMemberRead,
+		DeleteContext: resourceKongConsumerGroupMemberDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"consumer_group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"consumer_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceKongConsumerGroupMemberCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_consumer_group_member", "", true); diags.HasError() {
+		return diags
+	}
+
+	consumerGroupId := d.Get("consumer_group_id").(string)
+	consumerId := d.Get("consumer_id").(string)
+
+	client := meta.(*config).adminClient
+	err := addKongConsumerGroupMember(ctx, client, consumerGroupId, consumerId)
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to add consumer: %s to kong consumer group: %s error: %v", consumerId, consumerGroupId, err))
+	}
+
+	d.SetId(buildConsumerPairID(consumerGroupId, consumerId))
+
+	return resourceKongConsumerGroupMemberRead(ctx, d, meta)
+}
+
+func resourceKongConsumerGroupMemberRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	id, err := splitConsumerID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*config).adminClient
+	consumerGroup, err := getKongConsumerGroup(ctx, client, id.ID)
+
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not find kong consumer group: %s error: %v", id.ID, err))
+	}
+
+	member := false
+	for _, consumer := range consumerGroup.Consumers {
+		if consumer.ID != nil && *consumer.ID == id.ConsumerID {
+			member = true
+		}
+	}
+
+	if !member {
+		d.SetId("")
+	} else {
+		err = d.Set("consumer_group_id", id.ID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("consumer_id", id.ConsumerID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func resourceKongConsumerGroupMemberDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	id, err := splitConsumerID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*config).adminClient
+	err = removeKongConsumerGroupMember(ctx, client, id.ID, id.ConsumerID)
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("could not remove consumer: %s from kong consumer group: %s error: %v", id.ConsumerID, id.ID, err))
+	}
+
+	return diags
+}
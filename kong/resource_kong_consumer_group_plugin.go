@@ -0,0 +1,210 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func resourceKongConsumerGroupPlugin() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongConsumerGroupPluginCreate,
+		ReadContext:   resourceKongConsumerGroupPluginRead,
+		DeleteContext: resourceKongConsumerGroupPluginDelete,
+		UpdateContext: resourceKongConsumerGroupPluginUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"consumer_group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"config_json": {
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    normalizeDataJSON,
+				ValidateFunc: validateDataJSON,
+				Description:  "plugin configuration override in JSON format, configuration must be a valid JSON object.",
+			},
+			"computed_config": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKongConsumerGroupPluginCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_consumer_group_plugin", "", true); diags.HasError() {
+		return diags
+	}
+
+	consumerGroupId := d.Get("consumer_group_id").(string)
+
+	diags := putConsumerGroupPluginFromResourceData(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(buildConsumerGroupPluginID(consumerGroupId, d.Get("name").(string)))
+
+	return resourceKongConsumerGroupPluginRead(ctx, d, meta)
+}
+
+func resourceKongConsumerGroupPluginUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_consumer_group_plugin", "", true); diags.HasError() {
+		return diags
+	}
+
+	diags := putConsumerGroupPluginFromResourceData(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	return resourceKongConsumerGroupPluginRead(ctx, d, meta)
+}
+
+func resourceKongConsumerGroupPluginRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	consumerGroupId, name, err := splitConsumerGroupPluginID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*config).adminClient
+	plugin, err := getKongConsumerGroupPlugin(ctx, client, consumerGroupId, name)
+
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not find kong consumer group plugin: %s error: %v", d.Id(), err))
+	}
+
+	if plugin == nil {
+		d.SetId("")
+	} else {
+		err = d.Set("consumer_group_id", consumerGroupId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("name", name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// kong fills in the fields an override leaves out, config_json tracks kong's values of the fields that were
+		// configured so they drift, and takes kong's whole config when importing
+		upstreamJSON := pluginConfigJSONToString(plugin.Config)
+		configured, err := readConfigJSONMap(d)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to unmarshal config_json, err: %v", err))
+		}
+		if len(configured) == 0 {
+			err = d.Set("config_json", upstreamJSON)
+		} else {
+			upstreamConfig := map[string]interface{}(plugin.Config)
+			preserveVaultReferences(configured, upstreamConfig)
+			err = d.Set("config_json", pluginConfigJSONToString(configuredConfigFields(configured, upstreamConfig)))
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("computed_config", upstreamJSON)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func resourceKongConsumerGroupPluginDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	consumerGroupId, name, err := splitConsumerGroupPluginID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*config).adminClient
+	err = deleteKongConsumerGroupPlugin(ctx, client, consumerGroupId, name)
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("could not delete kong consumer group plugin: %v", err))
+	}
+
+	return diags
+}
+
+func putConsumerGroupPluginFromResourceData(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var configJSON map[string]interface{}
+
+	err := json.Unmarshal([]byte(d.Get("config_json").(string)), &configJSON)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to unmarshal config_json, err: %v", err))
+	}
+
+	pluginRequest := &kongConsumerGroupPlugin{
+		Name:   readStringPtrFromResource(d, "name"),
+		Config: configJSON,
+	}
+
+	client := meta.(*config).adminClient
+	_, err = putKongConsumerGroupPlugin(ctx, client, d.Get("consumer_group_id").(string), pluginRequest)
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to configure kong consumer group plugin: %s error: %v", *pluginRequest.Name, err))
+	}
+
+	return nil
+}
+
+// configuredConfigFields returns kong's values of the configured fields, descending into configured objects. A field
+// kong does not return is left out so it shows as a change.
+func configuredConfigFields(configured map[string]interface{}, upstream map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for key, value := range configured {
+		upstreamValue, ok := upstream[key]
+		if !ok {
+			continue
+		}
+		configuredMap, configuredIsMap := value.(map[string]interface{})
+		upstreamMap, upstreamIsMap := upstreamValue.(map[string]interface{})
+		if configuredIsMap && upstreamIsMap {
+			fields[key] = configuredConfigFields(configuredMap, upstreamMap)
+		} else {
+			fields[key] = upstreamValue
+		}
+	}
+	return fields
+}
+
+func buildConsumerGroupPluginID(consumerGroupID, name string) string {
+	return consumerGroupID + "|" + name
+}
+
+func splitConsumerGroupPluginID(value string) (string, string, error) {
+	v := strings.Split(value, "|")
+	if len(v) != 2 {
+		return "", "", fmt.Errorf("expecting there to be exactly 2 strings in ID but found %d", len(v))
+	}
+	return v[0], v[1], nil
+}
@@ -0,0 +1,310 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/containers"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestAccKongConsumerGroup(t *testing.T) {
+	skipUnlessEnterprise(t)
+	skipInDatabase(t, "consumer groups are not part of the declarative configuration the provider sends", containers.DatabaseOff)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKongConsumerGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testCreateConsumerGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongConsumerGroupExists("kong_consumer_group.gold"),
+					testAccCheckKongConsumerGroupMember("kong_consumer_group_member.member"),
+					resource.TestCheckResourceAttr("kong_consumer_group.gold", "name", "tf-acc-gold"),
+					resource.TestCheckResourceAttr("kong_consumer_group.gold", "tags.#", "1"),
+					testAccCheckForChildIDCorrect("kong_consumer_group.gold", "kong_consumer_group_member.member", "consumer_group_id"),
+					testAccCheckForChildIDCorrect("kong_consumer_group.gold", "kong_consumer_group_plugin.tier", "consumer_group_id"),
+					resource.TestCheckResourceAttr("kong_consumer_group_plugin.tier", "name", "rate-limiting-advanced"),
+				),
+			},
+			{
+				Config: testUpdateConsumerGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongConsumerGroupExists("kong_consumer_group.gold"),
+					resource.TestCheckResourceAttr("kong_consumer_group.gold", "name", "tf-acc-platinum"),
+					resource.TestCheckResourceAttr("kong_consumer_group.gold", "tags.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "kong_consumer_group.gold",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "kong_consumer_group_member.member",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:            "kong_consumer_group_plugin.tier",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"config_json"},
+			},
+		},
+	})
+}
+
+func TestKongPluginConsumerGroupRequestJSON(t *testing.T) {
+	plugin := &kongPlugin{
+		Plugin: &kong.Plugin{
+			Name:    kong.String("rate-limiting-advanced"),
+			Enabled: kong.Bool(true),
+		},
+		ConsumerGroup: &kongConsumerGroup{ID: kong.String("gold")},
+	}
+
+	out, err := json.Marshal(plugin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"name":"rate-limiting-advanced","enabled":true,"consumer_group":{"id":"gold"}}`
+	if string(out) != expected {
+		t.Fatalf("Error matching output and expected: %s vs %s", out, expected)
+	}
+
+	id := buildConsumerGroupPluginID("gold", "rate-limiting-advanced")
+	group, name, err := splitConsumerGroupPluginID(id)
+	if err != nil || group != "gold" || name != "rate-limiting-advanced" {
+		t.Fatalf("Error splitting consumer group plugin id %s: %s %s %v", id, group, name, err)
+	}
+}
+
+func TestPluginConsumerGroupScope(t *testing.T) {
+	server := fakekong.NewServer("3.4.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	ctx := context.Background()
+	pluginResource := p.ResourcesMap["kong_plugin"]
+
+	scoped := map[string]interface{}{"name": "rate-limiting", "consumer_group_id": "gold", "config_json": `{"minute":10}`}
+	d := schema.TestResourceDataRaw(t, pluginResource.Schema, scoped)
+	if diags := pluginResource.CreateContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if group := d.Get("consumer_group_id").(string); group != "gold" {
+		t.Fatalf("expected the plugin to be scoped to the consumer group, got %q", group)
+	}
+
+	unscoped := map[string]interface{}{"name": "rate-limiting", "config_json": `{"minute":10}`}
+	state := d.State()
+	diff, err := pluginResource.Diff(ctx, state, terraform.NewResourceConfigRaw(unscoped), p.Meta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = schema.InternalMap(pluginResource.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := pluginResource.UpdateContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if group := d.Get("consumer_group_id").(string); group != "" {
+		t.Errorf("expected removing consumer_group_id to unscope the plugin, got %q", group)
+	}
+
+	plugin, err := getKongPlugin(ctx, p.Meta().(*config).adminClient, d.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plugin.ConsumerGroup != nil {
+		t.Errorf("expected kong to have no consumer group for the plugin, got %v", *plugin.ConsumerGroup.ID)
+	}
+}
+
+func TestConsumerGroupPluginReadTracksConfiguredFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/consumer_groups/gold/overrides/plugins/rate-limiting-advanced" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"rate-limiting-advanced","config":{"limit":[20],"window_size":[60],"retry_after_jitter_max":0,"redis":{"host":"redis","port":6379}}}`))
+	}))
+	defer server.Close()
+
+	client, err := GetKongClient(Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &config{adminClient: client}
+	pluginResource := Provider().ResourcesMap["kong_consumer_group_plugin"]
+	id := buildConsumerGroupPluginID("gold", "rate-limiting-advanced")
+
+	d := pluginResource.Data(&terraform.InstanceState{ID: id, Attributes: map[string]string{
+		"config_json": `{"limit":[10],"redis":{"host":"redis"},"window_size":[60]}`,
+	}})
+	if diags := pluginResource.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := `{"limit":[20],"redis":{"host":"redis"},"window_size":[60]}`
+	if configJSON := d.Get("config_json").(string); configJSON != expected {
+		t.Errorf("expected config_json to track kong's values of the configured fields, got %s", configJSON)
+	}
+
+	imported := pluginResource.Data(&terraform.InstanceState{ID: id})
+	if diags := pluginResource.ReadContext(context.Background(), imported, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if configJSON := imported.Get("config_json").(string); configJSON != imported.Get("computed_config").(string) {
+		t.Errorf("expected an import to take kong's whole config, got %s", configJSON)
+	}
+}
+
+func testAccCheckKongConsumerGroupDestroy(state *terraform.State) error {
+
+	client := testAccProvider.Meta().(*config).adminClient
+
+	for _, rs := range getResourcesByType("kong_consumer_group", state) {
+		consumerGroup, err := getKongConsumerGroup(context.Background(), client, rs.Primary.ID)
+
+		if !kong.IsNotFoundErr(err) && err != nil {
+			return fmt.Errorf("error calling get consumer group by id: %v", err)
+		}
+
+		if consumerGroup != nil {
+			return fmt.Errorf("consumer group %s still exists, %+v", rs.Primary.ID, consumerGroup)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckKongConsumerGroupExists(resourceKey string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		consumerGroup, err := getKongConsumerGroup(context.Background(), testAccProvider.Meta().(*config).adminClient, rs.Primary.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if consumerGroup == nil || consumerGroup.ConsumerGroup == nil {
+			return fmt.Errorf("consumer group with id %v not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckKongConsumerGroupMember(resourceKey string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		id, err := splitConsumerID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		consumerGroup, err := getKongConsumerGroup(context.Background(), testAccProvider.Meta().(*config).adminClient, id.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, consumer := range consumerGroup.Consumers {
+			if IDToString(consumer.ID) == id.ConsumerID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("consumer %s is not a member of consumer group %s", id.ConsumerID, id.ID)
+	}
+}
+
+const testCreateConsumerGroupConfig = `
+resource "kong_consumer" "consumer" {
+	username  = "tf-acc-GroupUser"
+	custom_id = "789"
+}
+
+resource "kong_consumer_group" "gold" {
+	name = "tf-acc-gold"
+	tags = ["myTag"]
+}
+
+resource "kong_consumer_group_member" "member" {
+	consumer_group_id = kong_consumer_group.gold.id
+	consumer_id       = kong_consumer.consumer.id
+}
+
+resource "kong_consumer_group_plugin" "tier" {
+	consumer_group_id = kong_consumer_group.gold.id
+	name              = "rate-limiting-advanced"
+	config_json       = <<EOT
+	{
+		"limit": [100],
+		"window_size": [60],
+		"window_type": "sliding",
+		"retry_after_jitter_max": 0
+	}
+EOT
+}
+`
+
+const testUpdateConsumerGroupConfig = `
+resource "kong_consumer" "consumer" {
+	username  = "tf-acc-GroupUser"
+	custom_id = "789"
+}
+
+resource "kong_consumer_group" "gold" {
+	name = "tf-acc-platinum"
+	tags = ["myTag", "anotherTag"]
+}
+
+resource "kong_consumer_group_member" "member" {
+	consumer_group_id = kong_consumer_group.gold.id
+	consumer_id       = kong_consumer.consumer.id
+}
+
+resource "kong_consumer_group_plugin" "tier" {
+	consumer_group_id = kong_consumer_group.gold.id
+	name              = "rate-limiting-advanced"
+	config_json       = <<EOT
+	{
+		"limit": [1000],
+		"window_size": [60],
+		"window_type": "sliding",
+		"retry_after_jitter_max": 0
+	}
+EOT
+}
+`
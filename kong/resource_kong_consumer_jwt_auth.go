@@ -18,6 +18,12 @@ func resourceKongConsumerJWTAuth() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"consumer_id": {
@@ -41,9 +47,10 @@ func resourceKongConsumerJWTAuth() *schema.Resource {
 				ForceNew: false,
 			},
 			"secret": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: false,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         false,
+				DiffSuppressFunc: suppressHashedSecretDiff,
 			},
 			"tags": {
 				Type:     schema.TypeList,
@@ -56,12 +63,13 @@ func resourceKongConsumerJWTAuth() *schema.Resource {
 }
 
 func resourceKongConsumerJWTAuthCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 
 	JWTAuthRequest := &kong.JWTAuth{
 		Algorithm:    kong.String(d.Get("algorithm").(string)),
 		Key:          kong.String(d.Get("key").(string)),
 		RSAPublicKey: kong.String(d.Get("rsa_public_key").(string)),
-		Secret:       kong.String(d.Get("secret").(string)),
+		Secret:       readSecretWithEmptyPtrFromResource(d, "secret"),
 		Tags:         readStringArrayPtrFromResource(d, "tags"),
 	}
 
@@ -71,7 +79,7 @@ func resourceKongConsumerJWTAuthCreate(ctx context.Context, d *schema.ResourceDa
 	JWTAuth, err := client.Create(ctx, consumerId, JWTAuthRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong JWTAuth: %v error: %v", JWTAuthRequest, err))
+		return kongErrors.diagnostics("failed to create kong JWTAuth", resourceKongConsumerJWTAuth().Schema, fmt.Errorf("failed to create kong JWTAuth: %s error: %w", redactedRequest(JWTAuthRequest), err))
 	}
 
 	d.SetId(buildConsumerPairID(*JWTAuth.ID, *consumerId))
@@ -97,6 +105,7 @@ func splitConsumerID(value string) (*ConsumerIDPair, error) {
 }
 
 func resourceKongConsumerJWTAuthUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
 	id, err := splitConsumerID(d.Id())
@@ -106,7 +115,7 @@ func resourceKongConsumerJWTAuthUpdate(ctx context.Context, d *schema.ResourceDa
 		Algorithm:    kong.String(d.Get("algorithm").(string)),
 		Key:          kong.String(d.Get("key").(string)),
 		RSAPublicKey: kong.String(d.Get("rsa_public_key").(string)),
-		Secret:       kong.String(d.Get("secret").(string)),
+		Secret:       readSecretWithEmptyPtrFromResource(d, "secret"),
 		Tags:         readStringArrayPtrFromResource(d, "tags"),
 	}
 
@@ -116,7 +125,7 @@ func resourceKongConsumerJWTAuthUpdate(ctx context.Context, d *schema.ResourceDa
 	_, err = client.Update(ctx, consumerId, JWTAuthRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong JWTAuth: %s", err))
+		return kongErrors.diagnostics("error updating kong JWTAuth", resourceKongConsumerJWTAuth().Schema, fmt.Errorf("error updating kong JWTAuth: %w", err))
 	}
 
 	return resourceKongConsumerJWTAuthRead(ctx, d, meta)
@@ -149,7 +158,7 @@ func resourceKongConsumerJWTAuthRead(ctx context.Context, d *schema.ResourceData
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		err = d.Set("secret", JWTAuth.Secret)
+		err = d.Set("secret", secretStateValue(meta, JWTAuth.Secret))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
 )
 
 func TestAccJWTAuth(t *testing.T) {
@@ -45,6 +47,39 @@ func TestAccJWTAuth(t *testing.T) {
 	})
 }
 
+func TestAccJWTAuthWriteOnlySecret(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckJWTAuthDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testWriteOnlyJWTAuthConfig, "my_secret", `["foo", "bar"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckJWTAuthExists("kong_consumer_jwt_auth.consumer_jwt_config"),
+					resource.TestCheckResourceAttr("kong_consumer_jwt_auth.consumer_jwt_config", "secret", hashSecret("my_secret")),
+					testAccCheckJWTAuthSecret("kong_consumer_jwt_auth.consumer_jwt_config", "my_secret"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testWriteOnlyJWTAuthConfig, "my_secret", `["foo"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("kong_consumer_jwt_auth.consumer_jwt_config", "secret", hashSecret("my_secret")),
+					resource.TestCheckResourceAttr("kong_consumer_jwt_auth.consumer_jwt_config", "tags.#", "1"),
+					testAccCheckJWTAuthSecret("kong_consumer_jwt_auth.consumer_jwt_config", "my_secret"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testWriteOnlyJWTAuthConfig, "updated_secret", `["foo"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("kong_consumer_jwt_auth.consumer_jwt_config", "secret", hashSecret("updated_secret")),
+					testAccCheckJWTAuthSecret("kong_consumer_jwt_auth.consumer_jwt_config", "updated_secret"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccJWTAuthImport(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
@@ -63,6 +98,37 @@ func TestAccJWTAuthImport(t *testing.T) {
 	})
 }
 
+func TestJWTAuthSendsAnEmptySecretWhenNotWriteOnly(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*config).adminClient
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("jwt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwtResource := p.ResourcesMap["kong_consumer_jwt_auth"]
+	d := schema.TestResourceDataRaw(t, jwtResource.Schema, map[string]interface{}{"consumer_id": *consumer.ID, "key": "jwt-key", "algorithm": "HS256"})
+	if diags := jwtResource.CreateContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	jwts, _, err := client.JWTAuths.ListForConsumer(ctx, consumer.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jwts) != 1 || jwts[0].Secret == nil || *jwts[0].Secret != "" {
+		t.Errorf("expected a secret that is not set to be sent empty, got %+v", jwts)
+	}
+}
+
 func testAccCheckJWTAuthDestroy(state *terraform.State) error {
 
 	client := testAccProvider.Meta().(*config).adminClient.JWTAuths
@@ -117,9 +183,37 @@ func testAccCheckJWTAuthExists(resourceKey string) resource.TestCheckFunc {
 	}
 }
 
+func testAccCheckJWTAuthSecret(resourceKey string, secret string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		id, err := splitConsumerID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		client := testAccProvider.Meta().(*config).adminClient.JWTAuths
+		jwtAuth, err := client.Get(context.Background(), kong.String(id.ConsumerID), kong.String(id.ID))
+		if err != nil {
+			return err
+		}
+
+		if jwtAuth.Secret == nil || *jwtAuth.Secret != secret {
+			return fmt.Errorf("expected jwtAuth %v to have secret %s", id.ID, secret)
+		}
+
+		return nil
+	}
+}
+
 const testCreateJWTAuthConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -141,9 +235,28 @@ resource "kong_consumer_jwt_auth" "consumer_jwt_config" {
     tags           = ["foo", "bar"]
 }
 `
+const testWriteOnlyJWTAuthConfig = `
+provider "kong" {
+	write_only_secrets = true
+}
+
+resource "kong_consumer" "my_consumer" {
+	username  = "tf-acc-User1"
+	custom_id = "123"
+}
+
+resource "kong_consumer_jwt_auth" "consumer_jwt_config" {
+	consumer_id    = "${kong_consumer.my_consumer.id}"
+	algorithm      = "HS256"
+	key            = "my_key"
+	rsa_public_key = "foo"
+	secret         = "%s"
+	tags           = %s
+}
+`
 const testUpdateJWTAuthConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
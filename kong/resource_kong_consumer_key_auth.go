@@ -3,9 +3,11 @@ package kong
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kong/go-kong/kong"
 )
 
@@ -15,9 +17,16 @@ func resourceKongConsumerKeyAuth() *schema.Resource {
 		ReadContext:   resourceKongConsumerKeyAuthRead,
 		DeleteContext: resourceKongConsumerKeyAuthDelete,
 		UpdateContext: resourceKongConsumerKeyAuthUpdate,
+		CustomizeDiff: resourceKongConsumerKeyAuthCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"consumer_id": {
 				Type:     schema.TypeString,
@@ -31,6 +40,16 @@ func resourceKongConsumerKeyAuth() *schema.Resource {
 				ForceNew:  false,
 				Sensitive: true,
 			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"tags": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -42,8 +61,10 @@ func resourceKongConsumerKeyAuth() *schema.Resource {
 }
 
 func resourceKongConsumerKeyAuthCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	KeyAuthRequest := &kong.KeyAuth{
 		Key:  readStringPtrFromResource(d, "key"),
+		TTL:  readIntPtrFromResource(d, "ttl"),
 		Tags: readStringArrayPtrFromResource(d, "tags"),
 	}
 
@@ -53,20 +74,26 @@ func resourceKongConsumerKeyAuthCreate(ctx context.Context, d *schema.ResourceDa
 	keyAuth, err := client.Create(ctx, consumerId, KeyAuthRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong key auth: %v error: %v", KeyAuthRequest, err))
+		return kongErrors.diagnostics("failed to create kong key auth", resourceKongConsumerKeyAuth().Schema, fmt.Errorf("failed to create kong key auth: %s error: %w", redactedRequest(KeyAuthRequest), err))
 	}
 
 	d.SetId(buildConsumerPairID(*keyAuth.ID, *consumerId))
 
+	if err := d.Set("expires_at", keyAuthExpiresAt(KeyAuthRequest.TTL, time.Now())); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceKongConsumerKeyAuthRead(ctx, d, meta)
 }
 
 func resourceKongConsumerKeyAuthUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	id, err := splitConsumerID(d.Id())
 
 	KeyAuthRequest := &kong.KeyAuth{
 		ID:   kong.String(id.ID),
 		Key:  readStringPtrFromResource(d, "key"),
+		TTL:  keyAuthUpdateTTL(d, time.Now()),
 		Tags: readStringArrayPtrFromResource(d, "tags"),
 	}
 
@@ -76,7 +103,13 @@ func resourceKongConsumerKeyAuthUpdate(ctx context.Context, d *schema.ResourceDa
 	_, err = client.Update(ctx, consumerId, KeyAuthRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong key auth: %s", err))
+		return kongErrors.diagnostics("error updating kong key auth", resourceKongConsumerKeyAuth().Schema, fmt.Errorf("error updating kong key auth: %w", err))
+	}
+
+	if d.HasChange("ttl") {
+		if err := d.Set("expires_at", keyAuthExpiresAt(KeyAuthRequest.TTL, time.Now())); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourceKongConsumerKeyAuthRead(ctx, d, meta)
@@ -98,6 +131,11 @@ func resourceKongConsumerKeyAuthRead(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(fmt.Errorf("could not find kong key auth with id: %s error: %v", id, err))
 	}
 
+	if keyAuth != nil && keyAuthExpired(d.Get("expires_at").(string), time.Now()) {
+		// kong removes expired credentials lazily, one past its expiry is already gone
+		keyAuth = nil
+	}
+
 	if keyAuth == nil {
 		d.SetId("")
 	} else {
@@ -109,6 +147,13 @@ func resourceKongConsumerKeyAuthRead(ctx context.Context, d *schema.ResourceData
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		if d.Get("expires_at").(string) == "" && keyAuth.TTL != nil && *keyAuth.TTL > 0 {
+			// kong returns the seconds left before the credential expires
+			err = d.Set("expires_at", keyAuthExpiresAt(keyAuth.TTL, time.Now()))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
 		err = d.Set("tags", keyAuth.Tags)
 		if err != nil {
 			return diag.FromErr(err)
@@ -133,3 +178,51 @@ func resourceKongConsumerKeyAuthDelete(ctx context.Context, d *schema.ResourceDa
 
 	return diags
 }
+
+// resourceKongConsumerKeyAuthCustomizeDiff replaces the credential when its ttl is removed, kong can only set a ttl.
+func resourceKongConsumerKeyAuthCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("ttl") {
+		return nil
+	}
+
+	if old, new := d.GetChange("ttl"); old.(int) > 0 && new.(int) == 0 {
+		return d.ForceNew("ttl")
+	}
+
+	return d.SetNewComputed("expires_at")
+}
+
+// keyAuthUpdateTTL is the ttl to send with an update. A changed ttl starts a new expiry window, otherwise the time
+// left is sent so the update doesn't clear the expiry.
+func keyAuthUpdateTTL(d *schema.ResourceData, now time.Time) *int {
+	if d.HasChange("ttl") {
+		return readIntPtrFromResource(d, "ttl")
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, d.Get("expires_at").(string))
+	if err != nil {
+		return nil
+	}
+
+	remaining := int(expiresAt.Sub(now).Seconds())
+	if remaining < 1 {
+		remaining = 1
+	}
+
+	return kong.Int(remaining)
+}
+
+func keyAuthExpiresAt(ttl *int, now time.Time) string {
+	if ttl == nil || *ttl <= 0 {
+		return ""
+	}
+	return now.Add(time.Duration(*ttl) * time.Second).UTC().Format(time.RFC3339)
+}
+
+func keyAuthExpired(expiresAt string, now time.Time) bool {
+	expiry, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false
+	}
+	return !now.Before(expiry)
+}
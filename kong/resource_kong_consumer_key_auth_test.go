@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -68,6 +69,60 @@ func TestAccConsumerKeyAuthComputed(t *testing.T) {
 	})
 }
 
+func TestAccConsumerKeyAuthTTL(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsumerKeyAuthDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConsumerKeyAuthTTLConfig, 3600),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerKeyAuthExists("kong_consumer_key_auth.consumer_key_auth"),
+					resource.TestCheckResourceAttr("kong_consumer_key_auth.consumer_key_auth", "ttl", "3600"),
+					resource.TestCheckResourceAttrSet("kong_consumer_key_auth.consumer_key_auth", "expires_at"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerKeyAuthTTLConfig, 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerKeyAuthExists("kong_consumer_key_auth.consumer_key_auth"),
+					resource.TestCheckResourceAttr("kong_consumer_key_auth.consumer_key_auth", "ttl", "2"),
+					resource.TestCheckResourceAttrSet("kong_consumer_key_auth.consumer_key_auth", "expires_at"),
+				),
+			},
+			{
+				PreConfig: func() {
+					time.Sleep(3 * time.Second)
+				},
+				Config:             fmt.Sprintf(testConsumerKeyAuthTTLConfig, 2),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestKongKeyAuthExpiry(t *testing.T) {
+	now := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	if expiresAt := keyAuthExpiresAt(kong.Int(3600), now); expiresAt != "2021-11-01T13:00:00Z" {
+		t.Errorf("expected expiry an hour from now got %s", expiresAt)
+	}
+	if expiresAt := keyAuthExpiresAt(nil, now); expiresAt != "" {
+		t.Errorf("expected no expiry without a ttl got %s", expiresAt)
+	}
+	if keyAuthExpired("", now) {
+		t.Error("credential without an expiry should never be expired")
+	}
+	if keyAuthExpired("2021-11-01T12:00:01Z", now) {
+		t.Error("credential expiring in the future should not be expired")
+	}
+	if !keyAuthExpired("2021-11-01T12:00:00Z", now) {
+		t.Error("credential at its expiry should be expired")
+	}
+}
+
 func testAccCheckConsumerKeyAuthDestroy(state *terraform.State) error {
 
 	client := testAccProvider.Meta().(*config).adminClient.KeyAuths
@@ -124,7 +179,7 @@ func testAccCheckConsumerKeyAuthExists(resourceKey string) resource.TestCheckFun
 
 const testCreateConsumerKeyAuthConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -140,7 +195,7 @@ resource "kong_consumer_key_auth" "consumer_key_auth" {
 `
 const testUpdateConsumerKeyAuthConfig = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -156,7 +211,7 @@ resource "kong_consumer_key_auth" "consumer_key_auth" {
 `
 const testCreateConsumerKeyAuthConfigKeyComputed = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -169,3 +224,20 @@ resource "kong_consumer_key_auth" "consumer_key_auth" {
 	tags        = ["myTag"]
 }
 `
+
+const testConsumerKeyAuthTTLConfig = `
+resource "kong_consumer" "my_consumer" {
+	username  = "tf-acc-User1"
+	custom_id = "123"
+}
+
+resource "kong_plugin" "key_auth_plugin" {
+	name = "key-auth"
+}
+
+resource "kong_consumer_key_auth" "consumer_key_auth" {
+	consumer_id = "${kong_consumer.my_consumer.id}"
+	key         = "contractor"
+	ttl         = %d
+}
+`
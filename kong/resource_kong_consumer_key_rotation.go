@@ -0,0 +1,436 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/kong/go-kong/kong"
+)
+
+// rotationKey is a key-auth credential managed by a kong_consumer_key_rotation, CreatedAt is in unix seconds.
+type rotationKey struct {
+	ID        string
+	Key       string
+	CreatedAt int
+	Tags      []string
+}
+
+// rotationTagPrefix starts the tag that marks the keys of a rotation, it is followed by the name of the rotation so
+// the keys can be found again on import and after the key the rotation was created with is gone.
+const rotationTagPrefix = "key-rotation-"
+
+// rotationNamePattern limits the names of rotations to the characters kong accepts in tags.
+var rotationNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.~-]+$`)
+
+func resourceKongConsumerKeyRotation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongConsumerKeyRotationCreate,
+		ReadContext:   resourceKongConsumerKeyRotationRead,
+		DeleteContext: resourceKongConsumerKeyRotationDelete,
+		UpdateContext: resourceKongConsumerKeyRotationUpdate,
+		CustomizeDiff: resourceKongConsumerKeyRotationCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKongConsumerKeyRotationImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"consumer_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "default",
+				ValidateFunc: validation.StringMatch(rotationNamePattern, "must only contain letters, digits and the characters _.~-"),
+			},
+			"key_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     false,
+				Default:      2,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"rotation_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"overlap_period": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				Default:      "24h",
+				ValidateFunc: validateDuration,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"keys": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"key": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"created_at": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"active_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"retiring_keys": {
+				Type:      schema.TypeList,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKongConsumerKeyRotationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	consumerId := kong.String(d.Get("consumer_id").(string))
+
+	key, err := createRotationKey(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the keys come and go as they are rotated, the rotation is identified by its name and consumer instead.
+	d.SetId(buildConsumerPairID(d.Get("name").(string), *consumerId))
+
+	if err := setRotationKeys(d, []rotationKey{key}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceKongConsumerKeyRotationRead(ctx, d, meta)
+}
+
+func resourceKongConsumerKeyRotationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	consumerId := kong.String(d.Get("consumer_id").(string))
+	client := meta.(*config).adminClient.KeyAuths
+
+	// the keys are planned as unknown when they change, they are read from kong rather than from the plan.
+	keys, err := listRotationKeys(ctx, meta.(*config).adminClient, *consumerId, d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("could not list the kong key auths of consumer: %s error: %v", *consumerId, err))
+	}
+	sortRotationKeys(keys)
+
+	if d.HasChange("rotation_trigger") {
+		key, err := createRotationKey(ctx, d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		keys = append([]rotationKey{key}, keys...)
+	}
+
+	if d.HasChange("tags") {
+		for _, key := range keys {
+			_, err := client.Update(ctx, consumerId, &kong.KeyAuth{
+				ID:   kong.String(key.ID),
+				Tags: rotationTags(d),
+			})
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error updating kong key auth %s: %s", key.ID, err))
+			}
+		}
+	}
+
+	overlap, _ := time.ParseDuration(d.Get("overlap_period").(string))
+	expired := expiredRotationKeys(keys, d.Get("key_count").(int), overlap, time.Now())
+
+	var retained []rotationKey
+	for _, key := range keys {
+		if !expired[key.ID] {
+			retained = append(retained, key)
+			continue
+		}
+		err := client.Delete(ctx, consumerId, kong.String(key.ID))
+		if err != nil && !kong.IsNotFoundErr(err) {
+			return diag.FromErr(fmt.Errorf("could not delete kong key auth %s: %v", key.ID, err))
+		}
+	}
+
+	if err := setRotationKeys(d, retained); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceKongConsumerKeyRotationRead(ctx, d, meta)
+}
+
+func resourceKongConsumerKeyRotationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	consumerId := d.Get("consumer_id").(string)
+	keys, err := listRotationKeys(ctx, meta.(*config).adminClient, consumerId, d.Get("name").(string))
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+		return diags
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not list the kong key auths of consumer: %s error: %v", consumerId, err))
+	}
+
+	if len(keys) == 0 {
+		d.SetId("")
+		return diags
+	}
+
+	if err := setRotationKeys(d, keys); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceKongConsumerKeyRotationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	consumerId := kong.String(d.Get("consumer_id").(string))
+	client := meta.(*config).adminClient.KeyAuths
+
+	keys, err := listRotationKeys(ctx, meta.(*config).adminClient, *consumerId, d.Get("name").(string))
+	if kong.IsNotFoundErr(err) {
+		return diags
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not list the kong key auths of consumer: %s error: %v", *consumerId, err))
+	}
+
+	for _, key := range keys {
+		err := client.Delete(ctx, consumerId, kong.String(key.ID))
+		if err != nil && !kong.IsNotFoundErr(err) {
+			return diag.FromErr(fmt.Errorf("could not delete kong key auth %s: %v", key.ID, err))
+		}
+	}
+
+	return diags
+}
+
+// resourceKongConsumerKeyRotationImport imports the keys of a rotation by its name and consumer, the other arguments
+// start at their defaults.
+func resourceKongConsumerKeyRotationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := splitConsumerID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("expected an import id of the form <name>|<consumer_id>: %v", err)
+	}
+
+	resourceSchema := resourceKongConsumerKeyRotation().Schema
+	values := map[string]interface{}{
+		"name":           id.ID,
+		"consumer_id":    id.ConsumerID,
+		"key_count":      resourceSchema["key_count"].Default,
+		"overlap_period": resourceSchema["overlap_period"].Default,
+	}
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceKongConsumerKeyRotationCustomizeDiff plans a new key when the trigger changes and plans the removal of
+// keys whose overlap period has passed since the last apply.
+func resourceKongConsumerKeyRotationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	rotate := d.HasChange("rotation_trigger")
+
+	overlap, err := time.ParseDuration(d.Get("overlap_period").(string))
+	if err != nil {
+		return nil
+	}
+	keys := readRotationKeysFromList(d.Get("keys").([]interface{}))
+	prune := len(expiredRotationKeys(keys, d.Get("key_count").(int), overlap, time.Now())) > 0
+
+	if rotate || prune {
+		for _, key := range []string{"keys", "active_key", "retiring_keys"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func createRotationKey(ctx context.Context, d *schema.ResourceData, meta interface{}) (rotationKey, error) {
+	KeyAuthRequest := &kong.KeyAuth{
+		Tags: rotationTags(d),
+	}
+
+	consumerId := kong.String(d.Get("consumer_id").(string))
+
+	client := meta.(*config).adminClient.KeyAuths
+	keyAuth, err := client.Create(ctx, consumerId, KeyAuthRequest)
+
+	if err != nil {
+		return rotationKey{}, fmt.Errorf("failed to create kong key auth for consumer: %s error: %v", *consumerId, err)
+	}
+
+	key := rotationKeyFromKeyAuth(keyAuth)
+	if key.CreatedAt == 0 {
+		key.CreatedAt = int(time.Now().Unix())
+	}
+
+	return key, nil
+}
+
+// expiredRotationKeys returns the ids of the keys to delete from a list ordered newest first. The newest key is
+// always kept, older keys are kept until overlap has passed since the key that replaced them was created and at
+// most count keys are kept.
+func expiredRotationKeys(keys []rotationKey, count int, overlap time.Duration, now time.Time) map[string]bool {
+	expired := map[string]bool{}
+
+	for i := 1; i < len(keys); i++ {
+		replacedAt := time.Unix(int64(keys[i-1].CreatedAt), 0)
+		if i >= count || !now.Before(replacedAt.Add(overlap)) {
+			expired[keys[i].ID] = true
+		}
+	}
+
+	return expired
+}
+
+// rotationTags returns the tags of the keys of a rotation, the configured tags and the tag marking the rotation.
+func rotationTags(d *schema.ResourceData) []*string {
+	return append(readStringArrayPtrFromResource(d, "tags"), kong.String(rotationTagPrefix+d.Get("name").(string)))
+}
+
+// listRotationKeys returns the keys of a consumer marked with the tag of the named rotation.
+func listRotationKeys(ctx context.Context, client *kong.Client, consumerID string, name string) ([]rotationKey, error) {
+	marker := rotationTagPrefix + name
+
+	var keys []rotationKey
+	opt := &kong.ListOpt{Size: 1000}
+	for {
+		keyAuths, next, err := client.KeyAuths.ListForConsumer(ctx, kong.String(consumerID), opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, keyAuth := range keyAuths {
+			for _, tag := range keyAuth.Tags {
+				if tag != nil && *tag == marker {
+					keys = append(keys, rotationKeyFromKeyAuth(keyAuth))
+					break
+				}
+			}
+		}
+		if next == nil {
+			return keys, nil
+		}
+		opt = next
+	}
+}
+
+func rotationKeyFromKeyAuth(keyAuth *kong.KeyAuth) rotationKey {
+	key := rotationKey{
+		ID:  IDToString(keyAuth.ID),
+		Key: IDToString(keyAuth.Key),
+	}
+	if keyAuth.CreatedAt != nil {
+		key.CreatedAt = *keyAuth.CreatedAt
+	}
+	for _, tag := range keyAuth.Tags {
+		if tag != nil && !strings.HasPrefix(*tag, rotationTagPrefix) {
+			key.Tags = append(key.Tags, *tag)
+		}
+	}
+	return key
+}
+
+func readRotationKeysFromList(list []interface{}) []rotationKey {
+	var keys []rotationKey
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			keys = append(keys, rotationKey{
+				ID:        m["id"].(string),
+				Key:       m["key"].(string),
+				CreatedAt: m["created_at"].(int),
+			})
+		}
+	}
+	return keys
+}
+
+// sortRotationKeys orders keys newest first.
+func sortRotationKeys(keys []rotationKey) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt > keys[j].CreatedAt
+	})
+}
+
+func setRotationKeys(d *schema.ResourceData, keys []rotationKey) error {
+	sortRotationKeys(keys)
+
+	var list []map[string]interface{}
+	var retiring []string
+	for i, key := range keys {
+		list = append(list, map[string]interface{}{
+			"id":         key.ID,
+			"key":        key.Key,
+			"created_at": key.CreatedAt,
+		})
+		if i > 0 {
+			retiring = append(retiring, key.Key)
+		}
+	}
+
+	activeKey := ""
+	var tags []string
+	if len(keys) > 0 {
+		activeKey, tags = keys[0].Key, keys[0].Tags
+	}
+
+	if err := d.Set("keys", list); err != nil {
+		return err
+	}
+	if err := d.Set("active_key", activeKey); err != nil {
+		return err
+	}
+	if err := d.Set("tags", tags); err != nil {
+		return err
+	}
+	return d.Set("retiring_keys", retiring)
+}
+
+func validateDuration(durationI interface{}, _ string) ([]string, []error) {
+	if _, err := time.ParseDuration(durationI.(string)); err != nil {
+		return nil, []error{err}
+	}
+	return nil, nil
+}
@@ -0,0 +1,270 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestAccConsumerKeyRotation(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsumerKeyRotationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConsumerKeyRotationConfig, "first", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerKeyRotationExists("kong_consumer_key_rotation.rotation", 1),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "keys.#", "1"),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "retiring_keys.#", "0"),
+					resource.TestCheckResourceAttrPair("kong_consumer_key_rotation.rotation", "active_key", "kong_consumer_key_rotation.rotation", "keys.0.key"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerKeyRotationConfig, "second", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerKeyRotationExists("kong_consumer_key_rotation.rotation", 2),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "keys.#", "2"),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "retiring_keys.#", "1"),
+					resource.TestCheckResourceAttrPair("kong_consumer_key_rotation.rotation", "active_key", "kong_consumer_key_rotation.rotation", "keys.0.key"),
+					resource.TestCheckResourceAttrPair("kong_consumer_key_rotation.rotation", "retiring_keys.0", "kong_consumer_key_rotation.rotation", "keys.1.key"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerKeyRotationConfig, "third", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerKeyRotationExists("kong_consumer_key_rotation.rotation", 2),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "keys.#", "2"),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "retiring_keys.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConsumerKeyRotationConfig, "third", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsumerKeyRotationExists("kong_consumer_key_rotation.rotation", 1),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "keys.#", "1"),
+					resource.TestCheckResourceAttr("kong_consumer_key_rotation.rotation", "retiring_keys.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "kong_consumer_key_rotation.rotation",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the trigger is only known to the configuration and the key count and overlap period start at their defaults
+				ImportStateVerifyIgnore: []string{"rotation_trigger", "key_count", "overlap_period"},
+			},
+		},
+	})
+}
+
+func TestExpiredRotationKeys(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	keys := []rotationKey{
+		{ID: "newest", CreatedAt: 1000000 - 60},
+		{ID: "recent", CreatedAt: 1000000 - 7200},
+		{ID: "oldest", CreatedAt: 1000000 - 86400},
+	}
+
+	tests := []struct {
+		count    int
+		overlap  time.Duration
+		expected []string
+	}{
+		{count: 3, overlap: time.Hour, expected: []string{"oldest"}},
+		{count: 3, overlap: 24 * time.Hour, expected: []string{}},
+		{count: 2, overlap: 24 * time.Hour, expected: []string{"oldest"}},
+		{count: 1, overlap: 24 * time.Hour, expected: []string{"recent", "oldest"}},
+		{count: 1, overlap: 0, expected: []string{"recent", "oldest"}},
+	}
+
+	for _, test := range tests {
+		expired := expiredRotationKeys(keys, test.count, test.overlap, now)
+		if len(expired) != len(test.expected) {
+			t.Errorf("count %d overlap %s: expected %v expired got %v", test.count, test.overlap, test.expected, expired)
+			continue
+		}
+		for _, id := range test.expected {
+			if !expired[id] {
+				t.Errorf("count %d overlap %s: expected %s to be expired got %v", test.count, test.overlap, id, expired)
+			}
+		}
+		if expired["newest"] {
+			t.Errorf("count %d overlap %s: newest key should never expire", test.count, test.overlap)
+		}
+	}
+}
+
+func TestConsumerKeyRotationKeepsItsIDAcrossRotations(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	client := p.Meta().(*config).adminClient
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("rotated")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a key of the consumer that is not part of the rotation is left alone.
+	if _, err := client.KeyAuths.Create(ctx, consumer.ID, &kong.KeyAuth{Key: kong.String("unmanaged")}); err != nil {
+		t.Fatal(err)
+	}
+
+	rotationResource := p.ResourcesMap["kong_consumer_key_rotation"]
+	raw := map[string]interface{}{"consumer_id": *consumer.ID, "name": "partner", "rotation_trigger": "first", "key_count": 1, "tags": []interface{}{"partner"}}
+	d := schema.TestResourceDataRaw(t, rotationResource.Schema, raw)
+	if diags := rotationResource.CreateContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	id, firstKey := d.Id(), d.Get("active_key")
+	if id != buildConsumerPairID("partner", *consumer.ID) {
+		t.Fatalf("expected the rotation to be identified by its name and consumer, got %s", id)
+	}
+
+	state := d.State()
+	raw["rotation_trigger"] = "second"
+	diff, err := rotationResource.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), p.Meta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err = schema.InternalMap(rotationResource.Schema).Data(state, diff); err != nil {
+		t.Fatal(err)
+	}
+	if diags := rotationResource.UpdateContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != id || d.Get("active_key") == firstKey || d.Get("keys.#") != 1 {
+		t.Fatalf("expected the rotation to replace its first key under the same id, got %s with %v keys", d.Id(), d.Get("keys.#"))
+	}
+
+	// the first key is gone, reading the rotation still finds the second one.
+	d = rotationResource.Data(d.State())
+	if diags := rotationResource.ReadContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != id || d.Get("keys.#") != 1 || d.Get("tags.0") != "partner" {
+		t.Errorf("expected the rotation to be read after its first key was deleted, got %s with %v keys and tags %v", d.Id(), d.Get("keys.#"), d.Get("tags"))
+	}
+
+	imported, err := rotationResource.Importer.StateContext(ctx, rotationResource.Data(&terraform.InstanceState{ID: id}), p.Meta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := rotationResource.ReadContext(ctx, imported[0], p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if imported[0].Get("active_key") != d.Get("active_key") || imported[0].Get("name") != "partner" {
+		t.Errorf("expected the import to find the active key of the rotation, got %v", imported[0].Get("keys"))
+	}
+
+	if diags := rotationResource.DeleteContext(ctx, d, p.Meta()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	keyAuths, _, err := client.KeyAuths.ListForConsumer(ctx, consumer.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keyAuths) != 1 || *keyAuths[0].Key != "unmanaged" {
+		t.Errorf("expected only the key outside the rotation to be left, got %d keys", len(keyAuths))
+	}
+}
+
+func testAccCheckConsumerKeyRotationDestroy(state *terraform.State) error {
+
+	client := testAccProvider.Meta().(*config).adminClient.KeyAuths
+
+	for _, rs := range getResourcesByType("kong_consumer_key_rotation", state) {
+		consumerID := rs.Primary.Attributes["consumer_id"]
+
+		for _, keyID := range testAccConsumerKeyRotationIDs(rs) {
+			keyAuth, err := client.Get(context.Background(), kong.String(consumerID), kong.String(keyID))
+
+			if !kong.IsNotFoundErr(err) && err != nil {
+				return fmt.Errorf("error calling get consumer key auth by id: %v", err)
+			}
+
+			if keyAuth != nil {
+				return fmt.Errorf("key auth %s still exists, %+v", keyID, keyAuth)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckConsumerKeyRotationExists(resourceKey string, count int) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*config).adminClient.KeyAuths
+		consumerID := rs.Primary.Attributes["consumer_id"]
+
+		keyAuths, _, err := client.ListForConsumer(context.Background(), kong.String(consumerID), nil)
+		if err != nil {
+			return err
+		}
+
+		if len(keyAuths) != count {
+			return fmt.Errorf("expected %d key auths for consumer %s found %d", count, consumerID, len(keyAuths))
+		}
+
+		for _, keyID := range testAccConsumerKeyRotationIDs(rs) {
+			if _, err := client.Get(context.Background(), kong.String(consumerID), kong.String(keyID)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccConsumerKeyRotationIDs(rs *terraform.ResourceState) []string {
+	var ids []string
+	for i := 0; ; i++ {
+		id, ok := rs.Primary.Attributes[fmt.Sprintf("keys.%d.id", i)]
+		if !ok {
+			return ids
+		}
+		ids = append(ids, id)
+	}
+}
+
+const testConsumerKeyRotationConfig = `
+resource "kong_consumer" "my_consumer" {
+	username  = "tf-acc-User1"
+	custom_id = "123"
+}
+
+resource "kong_plugin" "key_auth_plugin" {
+	name = "key-auth"
+}
+
+resource "kong_consumer_key_rotation" "rotation" {
+	consumer_id      = "${kong_consumer.my_consumer.id}"
+	rotation_trigger = "%s"
+	key_count        = %d
+	overlap_period   = "1h"
+	tags             = ["myTag"]
+}
+`
@@ -15,6 +15,15 @@ func resourceKongConsumerOAuth2() *schema.Resource {
 		ReadContext:   resourceKongConsumerOAuth2Read,
 		DeleteContext: resourceKongConsumerOAuth2Delete,
 		UpdateContext: resourceKongConsumerOAuth2Update,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"consumer_id": {
 				Type:     schema.TypeString,
@@ -32,9 +41,10 @@ func resourceKongConsumerOAuth2() *schema.Resource {
 				ForceNew: false,
 			},
 			"client_secret": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: false,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         false,
+				DiffSuppressFunc: suppressHashedSecretDiff,
 			},
 			"hash_secret": {
 				Type:     schema.TypeBool,
@@ -59,10 +69,11 @@ func resourceKongConsumerOAuth2() *schema.Resource {
 }
 
 func resourceKongConsumerOAuth2Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	OAuth2CredentialRequest := &kong.Oauth2Credential{
 		Name:         readStringPtrFromResource(d, "name"),
 		ClientID:     readStringPtrFromResource(d, "client_id"),
-		ClientSecret: readStringPtrFromResource(d, "client_secret"),
+		ClientSecret: readSecretPtrFromResource(d, "client_secret"),
 		HashSecret:   readBoolPtrFromResource(d, "hash_secret"),
 		RedirectURIs: readStringArrayPtrFromResource(d, "redirect_uris"),
 		Tags:         readStringArrayPtrFromResource(d, "tags"),
@@ -74,7 +85,7 @@ func resourceKongConsumerOAuth2Create(ctx context.Context, d *schema.ResourceDat
 	oAuth2Credentials, err := client.Create(ctx, consumerId, OAuth2CredentialRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create oauth2 credentials: %v error: %v", OAuth2CredentialRequest, err))
+		return kongErrors.diagnostics("failed to create oauth2 credentials", resourceKongConsumerOAuth2().Schema, fmt.Errorf("failed to create oauth2 credentials: %s error: %w", redactedRequest(OAuth2CredentialRequest), err))
 	}
 
 	d.SetId(buildConsumerPairID(*oAuth2Credentials.ID, *consumerId))
@@ -83,13 +94,14 @@ func resourceKongConsumerOAuth2Create(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceKongConsumerOAuth2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	id, _ := splitConsumerID(d.Id())
 
 	OAuth2CredentialRequest := &kong.Oauth2Credential{
 		ID:           kong.String(id.ID),
 		Name:         readStringPtrFromResource(d, "name"),
 		ClientID:     readStringPtrFromResource(d, "client_id"),
-		ClientSecret: readStringPtrFromResource(d, "client_secret"),
+		ClientSecret: readSecretPtrFromResource(d, "client_secret"),
 		HashSecret:   readBoolPtrFromResource(d, "hash_secret"),
 		RedirectURIs: readStringArrayPtrFromResource(d, "redirect_uris"),
 		Tags:         readStringArrayPtrFromResource(d, "tags"),
@@ -101,7 +113,7 @@ func resourceKongConsumerOAuth2Update(ctx context.Context, d *schema.ResourceDat
 	_, err := client.Update(ctx, consumerId, OAuth2CredentialRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong oauth2 credentials: %s", err))
+		return kongErrors.diagnostics("error updating kong oauth2 credentials", resourceKongConsumerOAuth2().Schema, fmt.Errorf("error updating kong oauth2 credentials: %w", err))
 	}
 
 	return resourceKongConsumerOAuth2Read(ctx, d, meta)
@@ -138,7 +150,7 @@ func resourceKongConsumerOAuth2Read(ctx context.Context, d *schema.ResourceData,
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		err = d.Set("client_secret", oAuth2Credentials.ClientSecret)
+		err = d.Set("client_secret", secretStateValue(meta, oAuth2Credentials.ClientSecret))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -45,6 +45,11 @@ func TestAccConsumerOAuth2(t *testing.T) {
 					resource.TestCheckResourceAttr("kong_consumer_oauth2.consumer_oauth2", "tags.1", "anotherTag"),
 				),
 			},
+			{
+				ResourceName:      "kong_consumer_oauth2.consumer_oauth2",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -105,7 +110,7 @@ func testAccCheckConsumerOAuth2Exists(resourceKey string) resource.TestCheckFunc
 
 const testCreateConsumerOAuth2Config = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -133,7 +138,7 @@ resource "kong_consumer_oauth2" "consumer_oauth2" {
 `
 const testUpdateConsumerOAuth2Config = `
 resource "kong_consumer" "my_consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
 }
 
@@ -20,7 +20,7 @@ func TestAccKongConsumer(t *testing.T) {
 				Config: testCreateConsumerConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongConsumerExists("kong_consumer.consumer"),
-					resource.TestCheckResourceAttr("kong_consumer.consumer", "username", "User1"),
+					resource.TestCheckResourceAttr("kong_consumer.consumer", "username", "tf-acc-User1"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "custom_id", "123"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "tags.#", "2"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "tags.0", "a"),
@@ -31,7 +31,7 @@ func TestAccKongConsumer(t *testing.T) {
 				Config: testUpdateConsumerConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongConsumerExists("kong_consumer.consumer"),
-					resource.TestCheckResourceAttr("kong_consumer.consumer", "username", "User2"),
+					resource.TestCheckResourceAttr("kong_consumer.consumer", "username", "tf-acc-User2"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "custom_id", "456"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "tags.#", "1"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "tags.0", "a"),
@@ -51,7 +51,7 @@ func TestAccKongConsumerNilIDs(t *testing.T) {
 				Config: testCreateConsumerConfigNoCustomID,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongConsumerExists("kong_consumer.consumer"),
-					resource.TestCheckResourceAttr("kong_consumer.consumer", "username", "User3"),
+					resource.TestCheckResourceAttr("kong_consumer.consumer", "username", "tf-acc-User3"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "custom_id", ""),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "tags.#", "1"),
 					resource.TestCheckResourceAttr("kong_consumer.consumer", "tags.0", "c"),
@@ -134,21 +134,21 @@ func testAccCheckKongConsumerExists(resourceKey string) resource.TestCheckFunc {
 
 const testCreateConsumerConfig = `
 resource "kong_consumer" "consumer" {
-	username  = "User1"
+	username  = "tf-acc-User1"
 	custom_id = "123"
     tags      = ["a", "b"]
 }
 `
 const testUpdateConsumerConfig = `
 resource "kong_consumer" "consumer" {
-	username  = "User2"
+	username  = "tf-acc-User2"
 	custom_id = "456"
     tags      = ["a"] 
 }
 `
 const testCreateConsumerConfigNoCustomID = `
 resource "kong_consumer" "consumer" {
-	username = "User3"
+	username = "tf-acc-User3"
 	tags     = ["c"]
 }
 `
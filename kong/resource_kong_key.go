@@ -0,0 +1,277 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func resourceKongKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongKeyCreate,
+		ReadContext:   resourceKongKeyRead,
+		DeleteContext: resourceKongKeyDelete,
+		UpdateContext: resourceKongKeyUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"kid": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: false,
+			},
+			"set_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"jwk": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         false,
+				Sensitive:        true,
+				ExactlyOneOf:     []string{"jwk", "pem"},
+				ValidateFunc:     validateJWK,
+				DiffSuppressFunc: suppressEquivalentJWKDiff,
+			},
+			"pem": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"public_key": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"private_key": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							DiffSuppressFunc: suppressHashedSecretDiff,
+						},
+					},
+				},
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKongKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_key", "3.1.0", false); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	keyRequest := createKongKeyRequestFromResourceData(d)
+
+	client := meta.(*config).adminClient
+	key, err := createKongKey(ctx, client, keyRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("failed to create kong key", resourceKongKey().Schema, fmt.Errorf("failed to create kong key: %s error: %w", IDToString(keyRequest.KID), err))
+	}
+
+	d.SetId(*key.ID)
+
+	if err := setKongKeyMaterial(d, meta, keyRequest); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceKongKeyRead(ctx, d, meta)
+}
+
+func resourceKongKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_key", "3.1.0", false); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	keyRequest := createKongKeyRequestFromResourceData(d)
+	keyRequest.ID = kong.String(d.Id())
+
+	client := meta.(*config).adminClient
+	_, err := updateKongKey(ctx, client, keyRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("error updating kong key", resourceKongKey().Schema, fmt.Errorf("error updating kong key: %w", err))
+	}
+
+	if err := setKongKeyMaterial(d, meta, keyRequest); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceKongKeyRead(ctx, d, meta)
+}
+
+func resourceKongKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*config).adminClient
+	key, err := getKongKey(ctx, client, d.Id())
+
+	// kong deletes the keys of a key set together with the set
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not find kong key: %v", err))
+	}
+
+	if key == nil {
+		d.SetId("")
+	} else {
+		err = d.Set("name", key.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("kid", key.KID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		setID := ""
+		if key.Set != nil {
+			setID = IDToString(key.Set.ID)
+		}
+		err = d.Set("set_id", setID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if key.JWK != nil && !jwkMatches(d.Get("jwk").(string), *key.JWK) {
+			err = d.Set("jwk", secretStateValue(meta, key.JWK))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if key.PEM != nil {
+			err = d.Set("pem", flattenKongKeyPEM(d, meta, key.PEM))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		err = d.Set("tags", key.Tags)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func resourceKongKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*config).adminClient
+	err := deleteKongKey(ctx, client, d.Id())
+
+	if err != nil && !kong.IsNotFoundErr(err) {
+		return diag.FromErr(fmt.Errorf("could not delete kong key: %v", err))
+	}
+
+	return diags
+}
+
+func createKongKeyRequestFromResourceData(d *schema.ResourceData) *kongKey {
+	keyRequest := &kongKey{
+		Name: readStringPtrFromResource(d, "name"),
+		KID:  readStringPtrFromResource(d, "kid"),
+		JWK:  readSecretPtrFromResource(d, "jwk"),
+		Tags: readStringArrayPtrFromResource(d, "tags"),
+	}
+
+	if setID := readIdPtrFromResource(d, "set_id"); setID != nil {
+		keyRequest.Set = &kongKeySet{ID: setID}
+	}
+
+	if pems := d.Get("pem").([]interface{}); len(pems) > 0 && pems[0] != nil {
+		pem := pems[0].(map[string]interface{})
+		keyRequest.PEM = &kongKeyPEM{
+			PublicKey: kong.String(pem["public_key"].(string)),
+		}
+		if privateKey := unhashedSecret(pem["private_key"].(string)); privateKey != "" {
+			keyRequest.PEM.PrivateKey = kong.String(privateKey)
+		}
+	}
+
+	return keyRequest
+}
+
+// setKongKeyMaterial keeps the key material that was sent in state, kong does not return private key material on
+// every version.
+func setKongKeyMaterial(d *schema.ResourceData, meta interface{}, keyRequest *kongKey) error {
+	if keyRequest.JWK != nil {
+		if err := d.Set("jwk", secretStateValue(meta, keyRequest.JWK)); err != nil {
+			return err
+		}
+	}
+	if keyRequest.PEM != nil && keyRequest.PEM.PrivateKey != nil {
+		return d.Set("pem", []interface{}{map[string]interface{}{
+			"public_key":  IDToString(keyRequest.PEM.PublicKey),
+			"private_key": IDToString(secretStateValue(meta, keyRequest.PEM.PrivateKey)),
+		}})
+	}
+	return nil
+}
+
+func flattenKongKeyPEM(d *schema.ResourceData, meta interface{}, pem *kongKeyPEM) []interface{} {
+	privateKey := d.Get("pem.0.private_key").(string)
+	if pem.PrivateKey != nil {
+		privateKey = IDToString(secretStateValue(meta, pem.PrivateKey))
+	}
+
+	return []interface{}{map[string]interface{}{
+		"public_key":  IDToString(pem.PublicKey),
+		"private_key": privateKey,
+	}}
+}
+
+// jwkMatches reports whether the JWK kong returned agrees with the one in state. Members kong leaves out, such as
+// the private key parameters, are not compared and a hashed JWK can not be compared at all.
+func jwkMatches(stateJWK string, kongJWK string) bool {
+	if stateJWK == "" {
+		return false
+	}
+	if isHashedSecret(stateJWK) {
+		return true
+	}
+
+	var state, returned map[string]interface{}
+	if err := json.Unmarshal([]byte(stateJWK), &state); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(kongJWK), &returned); err != nil {
+		return false
+	}
+
+	for member, value := range returned {
+		if !reflect.DeepEqual(state[member], value) {
+			return false
+		}
+	}
+	return true
+}
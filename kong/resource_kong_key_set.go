@@ -0,0 +1,129 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func resourceKongKeySet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongKeySetCreate,
+		ReadContext:   resourceKongKeySetRead,
+		DeleteContext: resourceKongKeySetDelete,
+		UpdateContext: resourceKongKeySetUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: false,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKongKeySetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_key_set", "3.1.0", false); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	keySetRequest := &kongKeySet{
+		Name: readStringPtrFromResource(d, "name"),
+		Tags: readStringArrayPtrFromResource(d, "tags"),
+	}
+
+	client := meta.(*config).adminClient
+	keySet, err := createKongKeySet(ctx, client, keySetRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("failed to create kong key set", resourceKongKeySet().Schema, fmt.Errorf("failed to create kong key set: %s error: %w", redactedRequest(keySetRequest), err))
+	}
+
+	d.SetId(*keySet.ID)
+
+	return resourceKongKeySetRead(ctx, d, meta)
+}
+
+func resourceKongKeySetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_key_set", "3.1.0", false); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	keySetRequest := &kongKeySet{
+		ID:   kong.String(d.Id()),
+		Name: readStringPtrFromResource(d, "name"),
+		Tags: readStringArrayPtrFromResource(d, "tags"),
+	}
+
+	client := meta.(*config).adminClient
+	_, err := updateKongKeySet(ctx, client, keySetRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("error updating kong key set", resourceKongKeySet().Schema, fmt.Errorf("error updating kong key set: %w", err))
+	}
+
+	return resourceKongKeySetRead(ctx, d, meta)
+}
+
+func resourceKongKeySetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*config).adminClient
+	keySet, err := getKongKeySet(ctx, client, d.Id())
+
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not find kong key set: %v", err))
+	}
+
+	if keySet == nil {
+		d.SetId("")
+	} else {
+		err = d.Set("name", keySet.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("tags", keySet.Tags)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func resourceKongKeySetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*config).adminClient
+	err := deleteKongKeySet(ctx, client, d.Id())
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("could not delete kong key set: %v", err))
+	}
+
+	return diags
+}
@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -21,6 +22,12 @@ func resourceKongPlugin() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -43,6 +50,11 @@ func resourceKongPlugin() *schema.Resource {
 				Optional: true,
 				ForceNew: false,
 			},
+			"consumer_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
 			"enabled": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -80,16 +92,27 @@ func resourceKongPlugin() *schema.Resource {
 }
 
 func resourceKongPluginCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	pluginRequest, err := createKongPluginRequestFromResourceData(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	client := meta.(*config).adminClient.Plugins
-	plugin, err := client.Create(ctx, pluginRequest)
+	client := meta.(*config).adminClient
+	var plugin *kong.Plugin
+	if consumerGroupID := readIdPtrFromResource(d, "consumer_group_id"); consumerGroupID != nil {
+		// go-kong does not model the consumer group scope, only these plugins are sent without it
+		var created *kongPlugin
+		created, err = createKongPlugin(ctx, client, &kongPlugin{Plugin: pluginRequest, ConsumerGroup: &kongConsumerGroup{ID: consumerGroupID}})
+		if created != nil {
+			plugin = created.Plugin
+		}
+	} else {
+		plugin, err = client.Plugins.Create(ctx, pluginRequest)
+	}
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong plugin: %v error: %v", pluginRequest, err))
+		return kongErrors.diagnostics("failed to create kong plugin", resourceKongPlugin().Schema, fmt.Errorf("failed to create kong plugin: %s error: %w", redactedRequest(pluginRequest), err))
 	}
 
 	d.SetId(*plugin.ID)
@@ -98,6 +121,7 @@ func resourceKongPluginCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceKongPluginUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
 	pluginRequest, err := createKongPluginRequestFromResourceData(d)
@@ -105,11 +129,20 @@ func resourceKongPluginUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	client := meta.(*config).adminClient.Plugins
-	_, err = client.Update(ctx, pluginRequest)
+	client := meta.(*config).adminClient
+	if consumerGroupID := readIdPtrFromResource(d, "consumer_group_id"); consumerGroupID != nil || d.HasChange("consumer_group_id") {
+		// a nil consumer group is sent as null so removing consumer_group_id unscopes the plugin
+		plugin := &kongPlugin{Plugin: pluginRequest}
+		if consumerGroupID != nil {
+			plugin.ConsumerGroup = &kongConsumerGroup{ID: consumerGroupID}
+		}
+		_, err = updateKongPlugin(ctx, client, plugin)
+	} else {
+		_, err = client.Plugins.Update(ctx, pluginRequest)
+	}
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong plugin: %s", err))
+		return kongErrors.diagnostics("error updating kong plugin", resourceKongPlugin().Schema, fmt.Errorf("error updating kong plugin: %w", err))
 	}
 
 	return resourceKongPluginRead(ctx, d, meta)
@@ -117,8 +150,9 @@ func resourceKongPluginUpdate(ctx context.Context, d *schema.ResourceData, meta
 
 func resourceKongPluginRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*config).adminClient.Plugins
-	plugin, err := client.Get(ctx, kong.String(d.Id()))
+	client := meta.(*config).adminClient
+	// read with the consumer group scope go-kong drops, so a plugin unscoped outside terraform shows as a change
+	plugin, err := getKongPlugin(ctx, client, d.Id())
 
 	if !kong.IsNotFoundErr(err) && err != nil {
 		return diag.FromErr(fmt.Errorf("could not find kong plugin: %v", err))
@@ -150,6 +184,14 @@ func resourceKongPluginRead(ctx context.Context, d *schema.ResourceData, meta in
 				return diag.FromErr(err)
 			}
 		}
+		if plugin.ConsumerGroup != nil {
+			err = d.Set("consumer_group_id", plugin.ConsumerGroup.ID)
+		} else {
+			err = d.Set("consumer_group_id", nil)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
 		err = d.Set("enabled", plugin.Enabled)
 		if err != nil {
 			return diag.FromErr(err)
@@ -162,7 +204,12 @@ func resourceKongPluginRead(ctx context.Context, d *schema.ResourceData, meta in
 		// We sync this property from upstream as a method to allow you to import a resource with the config tracked in
 		// terraform state. We do not track `config` as it will be a source of a perpetual diff.
 		// https://www.terraform.io/docs/extend/best-practices/detecting-drift.html#capture-all-state-in-read
-		upstreamJSON := pluginConfigJSONToString(plugin.Config)
+		upstreamConfig := map[string]interface{}(plugin.Config)
+		if configured, err := readConfigJSONMap(d); err == nil {
+			// a vault reference is stored as written, keep kong's copy of it from showing as a change
+			preserveVaultReferences(configured, upstreamConfig)
+		}
+		upstreamJSON := pluginConfigJSONToString(upstreamConfig)
 		setConfig := func(strict bool) error {
 			if strict {
 				err := d.Set("config_json", upstreamJSON)
@@ -260,7 +307,7 @@ func pluginConfigJSONToString(data map[string]interface{}) string {
 		}
 	}
 	// We know it is valid JSON at this point
-	rawJSON, _ := json.Marshal(marshalledData)
+	rawJSON, _ := marshalDataJSON(marshalledData)
 
 	return string(rawJSON)
 }
@@ -286,7 +333,7 @@ func normalizeDataJSON(configI interface{}) string {
 		return ""
 	}
 
-	ret, err := json.Marshal(dataMap)
+	ret, err := marshalDataJSON(dataMap)
 	if err != nil {
 		// Should never happen.
 		log.Printf("[ERROR] Problem normalizing JSON for config_json: %s", err)
@@ -295,3 +342,30 @@ func normalizeDataJSON(configI interface{}) string {
 
 	return string(ret)
 }
+
+// marshalDataJSON marshals config holding vault references without escaping HTML characters, so references such as
+// {vault://aws/secret?region=eu-west-1&version=2} stay as they were written. Other config is escaped as it always was
+// so the config_json already in state does not change.
+func marshalDataJSON(data interface{}) ([]byte, error) {
+	if !hasVaultReference(data) {
+		return json.Marshal(data)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func readConfigJSONMap(d *schema.ResourceData) (map[string]interface{}, error) {
+	configJSON := map[string]interface{}{}
+	if data, ok := d.GetOk("config_json"); ok {
+		if err := json.Unmarshal([]byte(data.(string)), &configJSON); err != nil {
+			return nil, err
+		}
+	}
+	return configJSON, nil
+}
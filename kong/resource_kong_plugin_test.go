@@ -365,7 +365,7 @@ EOT
 
 const testCreatePluginForASpecificConsumerConfig = `
 resource "kong_consumer" "plugin_consumer" {
-	username  = "PluginUser"
+	username  = "tf-acc-PluginUser"
 	custom_id = "567"
 }
 
@@ -383,7 +383,7 @@ EOT
 
 const testUpdatePluginForASpecificConsumerConfig = `
 resource "kong_consumer" "plugin_consumer" {
-	username  = "PluginUser"
+	username  = "tf-acc-PluginUser"
 	custom_id = "567"
 }
 
@@ -401,7 +401,7 @@ EOT
 
 const testCreatePluginForASpecificServiceConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -422,7 +422,7 @@ EOT
 
 const testUpdatePluginForASpecificServiceConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -442,7 +442,7 @@ EOT
 
 const testCreatePluginForASpecificRouteConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -471,7 +471,7 @@ EOT
 
 const testUpdatePluginForASpecificRouteConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -15,9 +15,16 @@ func resourceKongRoute() *schema.Resource {
 		ReadContext:   resourceKongRouteRead,
 		DeleteContext: resourceKongRouteDelete,
 		UpdateContext: resourceKongRouteUpdate,
+		CustomizeDiff: versionedAttributesCustomizeDiff(func() map[string]*schema.Schema { return resourceKongRoute().Schema }, routeVersionedAttributes),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -164,14 +171,28 @@ func resourceKongRoute() *schema.Resource {
 	}
 }
 
+// routeVersionedAttributes are the route attributes that only newer versions of kong accept.
+var routeVersionedAttributes = []versionedAttribute{
+	{name: "path_handling", minimumVersion: "2.0.0"},
+	{name: "request_buffering", minimumVersion: "2.3.0"},
+	{name: "response_buffering", minimumVersion: "2.3.0"},
+}
+
 func resourceKongRouteCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	versionDiags, omitted := meta.(*config).checkVersionedAttributes(d, resourceKongRoute().Schema, routeVersionedAttributes)
+	if versionDiags.HasError() {
+		return versionDiags
+	}
 
 	routeRequest := createKongRouteRequestFromResourceData(d)
+	omitRouteAttributes(routeRequest, omitted)
 
 	client := meta.(*config).adminClient.Routes
 	route, err := client.Create(ctx, routeRequest)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong route: %v error: %v", routeRequest, err))
+		return kongErrors.diagnostics("failed to create kong route", resourceKongRoute().Schema, fmt.Errorf("failed to create kong route: %s error: %w", redactedRequest(routeRequest), err))
 	}
 
 	d.SetId(*route.ID)
@@ -180,16 +201,23 @@ func resourceKongRouteCreate(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func resourceKongRouteUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
+	versionDiags, omitted := meta.(*config).checkVersionedAttributes(d, resourceKongRoute().Schema, routeVersionedAttributes)
+	if versionDiags.HasError() {
+		return versionDiags
+	}
+
 	routeRequest := createKongRouteRequestFromResourceData(d)
+	omitRouteAttributes(routeRequest, omitted)
 
 	client := meta.(*config).adminClient.Routes
 
 	_, err := client.Update(ctx, routeRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong route: %s", err))
+		return kongErrors.diagnostics("error updating kong route", resourceKongRoute().Schema, fmt.Errorf("error updating kong route: %w", err))
 	}
 
 	return resourceKongRouteRead(ctx, d, meta)
@@ -354,6 +382,19 @@ func resourceKongRouteDelete(ctx context.Context, d *schema.ResourceData, meta i
 	return diags
 }
 
+// omitRouteAttributes leaves the attributes a kong node is too old to know out of the request.
+func omitRouteAttributes(route *kong.Route, omitted map[string]bool) {
+	if omitted["path_handling"] {
+		route.PathHandling = nil
+	}
+	if omitted["request_buffering"] {
+		route.RequestBuffering = nil
+	}
+	if omitted["response_buffering"] {
+		route.ResponseBuffering = nil
+	}
+}
+
 func createKongRouteRequestFromResourceData(d *schema.ResourceData) *kong.Route {
 
 	route := &kong.Route{
@@ -19,7 +19,7 @@ func TestAccKongRoute(t *testing.T) {
 				Config: testCreateRouteConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongRouteExists("kong_route.route"),
-					resource.TestCheckResourceAttr("kong_route.route", "name", "foo"),
+					resource.TestCheckResourceAttr("kong_route.route", "name", "tf-acc-foo"),
 					resource.TestCheckResourceAttr("kong_route.route", "protocols.#", "1"),
 					resource.TestCheckResourceAttr("kong_route.route", "protocols.0", "http"),
 					resource.TestCheckResourceAttr("kong_route.route", "methods.#", "1"),
@@ -51,7 +51,7 @@ func TestAccKongRoute(t *testing.T) {
 				Config: testUpdateRouteConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongRouteExists("kong_route.route"),
-					resource.TestCheckResourceAttr("kong_route.route", "name", "bar"),
+					resource.TestCheckResourceAttr("kong_route.route", "name", "tf-acc-bar"),
 					resource.TestCheckResourceAttr("kong_route.route", "protocols.#", "2"),
 					resource.TestCheckResourceAttr("kong_route.route", "protocols.0", "http"),
 					resource.TestCheckResourceAttr("kong_route.route", "protocols.1", "https"),
@@ -199,13 +199,13 @@ func testAccCheckKongRouteExists(resourceKey string) resource.TestCheckFunc {
 
 const testCreateRouteConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
 
 resource "kong_route" "route" {
-	name            = "foo"
+	name            = "tf-acc-foo"
 	protocols 		= [ "http" ]
 	methods 		= [ "GET" ]
 	hosts 			=	[ "example.com" ]
@@ -231,13 +231,13 @@ resource "kong_route" "route" {
 `
 const testUpdateRouteConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
 
 resource "kong_route" "route" {
-	name            = "bar"
+	name            = "tf-acc-bar"
 	protocols 		= [ "http", "https" ]
 	methods 		= [ "GET", "POST" ]
 	hosts 			= [ "example2.com" ]
@@ -260,7 +260,7 @@ resource "kong_route" "route" {
 
 const testCreateRouteWithSourcesAndDestinationsConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -288,7 +288,7 @@ resource "kong_route" "route" {
 
 const testUpdateRouteWithSourcesAndDestinationsConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -315,7 +315,7 @@ resource "kong_route" "route" {
 `
 const testImportRouteConfig = `
 resource "kong_service" "service" {
-	name     = "test"
+	name     = "tf-acc-test"
 	protocol = "http"
 	host     = "test.org"
 }
@@ -18,6 +18,12 @@ func resourceKongService() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -103,13 +109,14 @@ func resourceKongService() *schema.Resource {
 }
 
 func resourceKongServiceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 
 	serviceRequest := createKongServiceRequestFromResourceData(d)
 
 	client := meta.(*config).adminClient.Services
 	service, err := client.Create(ctx, serviceRequest)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong service: %v error: %v", serviceRequest, err))
+		return kongErrors.diagnostics("failed to create kong service", resourceKongService().Schema, fmt.Errorf("failed to create kong service: %s error: %w", redactedRequest(serviceRequest), err))
 	}
 
 	d.SetId(*service.ID)
@@ -118,6 +125,7 @@ func resourceKongServiceCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceKongServiceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
 	serviceRequest := createKongServiceRequestFromResourceData(d)
@@ -126,7 +134,7 @@ func resourceKongServiceUpdate(ctx context.Context, d *schema.ResourceData, meta
 	_, err := client.Update(ctx, serviceRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong service: %s", err))
+		return kongErrors.diagnostics("error updating kong service", resourceKongService().Schema, fmt.Errorf("error updating kong service: %w", err))
 	}
 
 	return resourceKongServiceRead(ctx, d, meta)
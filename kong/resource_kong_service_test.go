@@ -19,7 +19,7 @@ func TestAccKongService(t *testing.T) {
 				Config: testCreateServiceConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongServiceExists("kong_service.service"),
-					resource.TestCheckResourceAttr("kong_service.service", "name", "test"),
+					resource.TestCheckResourceAttr("kong_service.service", "name", "tf-acc-test"),
 					resource.TestCheckResourceAttr("kong_service.service", "protocol", "http"),
 					resource.TestCheckResourceAttr("kong_service.service", "host", "test.org"),
 					resource.TestCheckResourceAttr("kong_service.service", "port", "80"),
@@ -37,7 +37,7 @@ func TestAccKongService(t *testing.T) {
 				Config: testUpdateServiceConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongServiceExists("kong_service.service"),
-					resource.TestCheckResourceAttr("kong_service.service", "name", "test2"),
+					resource.TestCheckResourceAttr("kong_service.service", "name", "tf-acc-test2"),
 					resource.TestCheckResourceAttr("kong_service.service", "protocol", "https"),
 					resource.TestCheckResourceAttr("kong_service.service", "host", "test2.org"),
 					resource.TestCheckResourceAttr("kong_service.service", "path", "/"),
@@ -87,7 +87,7 @@ func TestAccKongServiceWithClientCertificate(t *testing.T) {
 				Config: fmt.Sprintf(testServiceWithClientCertificateConfig, testCert1, testKey1, testCert2, testKey2),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongServiceExists("kong_service.service"),
-					resource.TestCheckResourceAttr("kong_service.service", "name", "test"),
+					resource.TestCheckResourceAttr("kong_service.service", "name", "tf-acc-test"),
 					resource.TestCheckResourceAttr("kong_service.service", "protocol", "https"),
 					resource.TestCheckResourceAttr("kong_service.service", "host", "test.org"),
 					func(s *terraform.State) error {
@@ -257,7 +257,7 @@ func testAccDeleteExistingKongService(resourceKey string) resource.TestCheckFunc
 
 const testCreateServiceConfig = `
 resource "kong_service" "service" {
-	name     		 = "test"
+	name     		 = "tf-acc-test"
 	protocol 		 = "http"
 	host     		 = "test.org"
 	path     		 = "/mypath"
@@ -271,7 +271,7 @@ resource "kong_service" "service" {
 
 const testUpdateServiceConfig = `
 resource "kong_service" "service" {
-	name     		 = "test2"
+	name     		 = "tf-acc-test2"
 	protocol 		 = "https"
 	host     		 = "test2.org"
 	port     		 = 8081
@@ -287,7 +287,7 @@ resource "kong_service" "service" {
 
 const testCreateServiceConfigZero = `
 resource "kong_service" "service" {
-	name     		= "test"
+	name     		= "tf-acc-test"
 	protocol 		= "http"
 	host     		= "test.org"
 	path     		= "/mypath"
@@ -300,7 +300,7 @@ resource "kong_service" "service" {
 
 const testUpdateServiceConfigZero = `
 resource "kong_service" "service" {
-	name     		= "test2"
+	name     		= "tf-acc-test2"
 	protocol 		= "https"
 	host     		= "test2.org"
 	port     		= 8081
@@ -321,6 +321,7 @@ EOF
 %s
 EOF
    snis			= ["foo.com"]
+   tags			= ["tf-acc-certificate"]
 }
 
 resource "kong_certificate" "ca" {
@@ -331,10 +332,11 @@ EOF
 %s
 EOF
    snis			= ["ca.com"]
+   tags			= ["tf-acc-certificate"]
 }
 
 resource "kong_service" "service" {
-	name                  = "test"
+	name                  = "tf-acc-test"
 	protocol              = "https"
 	host                  = "test.org"
 	client_certificate_id = kong_certificate.certificate.id
@@ -343,7 +345,7 @@ resource "kong_service" "service" {
 
 const testImportServiceConfig = `
 resource "kong_service" "service" {
-	name     		= "test"
+	name     		= "tf-acc-test"
 	protocol 		= "http"
 	host     		= "test.org"
 	port     		= 8080
@@ -7,17 +7,31 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kong/go-kong/kong"
 )
 
+const (
+	targetHealthOverrideNone      = "none"
+	targetHealthOverrideHealthy   = "healthy"
+	targetHealthOverrideUnhealthy = "unhealthy"
+)
+
 func resourceKongTarget() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceKongTargetCreate,
 		ReadContext:   resourceKongTargetRead,
 		DeleteContext: resourceKongTargetDelete,
+		UpdateContext: resourceKongTargetUpdate,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"target": {
@@ -41,11 +55,24 @@ func resourceKongTarget() *schema.Resource {
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"health_override": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+				Default:  targetHealthOverrideNone,
+				ValidateFunc: validation.StringInSlice([]string{
+					targetHealthOverrideNone,
+					targetHealthOverrideHealthy,
+					targetHealthOverrideUnhealthy,
+				}, false),
+				Description: "Force the target healthy or unhealthy in the load balancer, or none to leave it to the health checks",
+			},
 		},
 	}
 }
 
 func resourceKongTargetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 
 	targetRequest := createKongTargetRequestFromResourceData(d)
 
@@ -53,11 +80,31 @@ func resourceKongTargetCreate(ctx context.Context, d *schema.ResourceData, meta
 	target, err := client.Create(ctx, readStringPtrFromResource(d, "upstream_id"), targetRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong target: %v error: %v", targetRequest, err))
+		return kongErrors.diagnostics("failed to create kong target", resourceKongTarget().Schema, fmt.Errorf("failed to create kong target: %s error: %w", redactedRequest(targetRequest), err))
 	}
 
 	d.SetId(IDToString(target.Upstream.ID) + "/" + *target.ID)
 
+	if err := setKongTargetHealth(ctx, d, meta, d.Get("health_override").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceKongTargetRead(ctx, d, meta)
+}
+
+func resourceKongTargetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("health_override") {
+		previous, current := d.GetChange("health_override")
+		health := current.(string)
+		// Lifting a drain puts the target back into rotation, from there the health checks take over again.
+		if health == targetHealthOverrideNone && previous.(string) == targetHealthOverrideUnhealthy {
+			health = targetHealthOverrideHealthy
+		}
+		if err := setKongTargetHealth(ctx, d, meta, health); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceKongTargetRead(ctx, d, meta)
 }
 
@@ -109,11 +156,67 @@ func resourceKongTargetRead(ctx context.Context, d *schema.ResourceData, meta in
 
 	if recreate {
 		d.SetId("")
+		return diags
+	}
+
+	if err := reassertKongTargetHealth(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return diags
 }
 
+// reassertKongTargetHealth marks the target again when Kong reports the opposite of the configured
+// override, e.g. because the health counters were reset by a restart or by someone using the admin api.
+func reassertKongTargetHealth(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	override := d.Get("health_override").(string)
+	if override == "" {
+		// the override is not stored in kong, so an imported target starts without one
+		return d.Set("health_override", targetHealthOverrideNone)
+	}
+	if override == targetHealthOverrideNone {
+		return nil
+	}
+
+	var ids = strings.Split(d.Id(), "/")
+	client := meta.(*config).adminClient.UpstreamNodeHealth
+	nodes, err := client.ListAll(ctx, kong.String(ids[0]))
+	if err != nil {
+		return fmt.Errorf("could not read kong target health: %v", err)
+	}
+
+	for _, node := range nodes {
+		if node.ID == nil || *node.ID != ids[1] || node.Health == nil {
+			continue
+		}
+		health := strings.ToLower(*node.Health)
+		if (health == targetHealthOverrideHealthy || health == targetHealthOverrideUnhealthy) && health != override {
+			return setKongTargetHealth(ctx, d, meta, override)
+		}
+	}
+
+	return nil
+}
+
+func setKongTargetHealth(ctx context.Context, d *schema.ResourceData, meta interface{}, health string) error {
+	var ids = strings.Split(d.Id(), "/")
+	client := meta.(*config).adminClient.Targets
+	target := &kong.Target{ID: kong.String(ids[1])}
+
+	switch health {
+	case targetHealthOverrideHealthy:
+		if err := client.MarkHealthy(ctx, kong.String(ids[0]), target); err != nil {
+			return fmt.Errorf("could not mark kong target healthy: %v", err)
+		}
+	case targetHealthOverrideUnhealthy:
+		if err := client.MarkUnhealthy(ctx, kong.String(ids[0]), target); err != nil {
+			return fmt.Errorf("could not mark kong target unhealthy: %v", err)
+		}
+	}
+
+	return nil
+}
+
 func resourceKongTargetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	var ids = strings.Split(d.Id(), "/")
@@ -52,6 +52,40 @@ func TestAccKongTarget(t *testing.T) {
 	})
 }
 
+func TestAccKongTargetHealthOverride(t *testing.T) {
+	// kong only reports the health of targets whose upstream has health checks, the test upstream gets passive ones.
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKongTargetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testCreateTargetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongTargetExists("kong_target.target"),
+					resource.TestCheckResourceAttr("kong_target.target", "health_override", "none"),
+				),
+			},
+			{
+				Config: testUnhealthyTargetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongTargetExists("kong_target.target"),
+					resource.TestCheckResourceAttr("kong_target.target", "health_override", "unhealthy"),
+					testAccCheckKongTargetHealth("kong_target.target", "UNHEALTHY"),
+				),
+			},
+			{
+				Config: testHealthyTargetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongTargetExists("kong_target.target"),
+					resource.TestCheckResourceAttr("kong_target.target", "health_override", "healthy"),
+					testAccCheckKongTargetHealth("kong_target.target", "HEALTHY"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccKongTargetDelete(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
@@ -290,6 +324,34 @@ func testAccDeleteExistingKongTarget(resourceKey string) resource.TestCheckFunc
 	}
 }
 
+func testAccCheckKongTargetHealth(resourceKey string, health string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		ids := strings.Split(rs.Primary.ID, "/")
+		client := testAccProvider.Meta().(*config).adminClient.UpstreamNodeHealth
+		nodes, err := client.ListAll(context.Background(), kong.String(ids[0]))
+		if err != nil {
+			return err
+		}
+
+		for _, node := range nodes {
+			if node.ID != nil && *node.ID == ids[1] {
+				if node.Health == nil || *node.Health != health {
+					return fmt.Errorf("expected target %s to be %s but was %s", rs.Primary.ID, health, IDToString(node.Health))
+				}
+				return nil
+			}
+		}
+
+		return fmt.Errorf("no health found for target %s", rs.Primary.ID)
+	}
+}
+
 func deleteUpstream(upstreamResourceKey string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[upstreamResourceKey]
@@ -309,7 +371,7 @@ func deleteUpstream(upstreamResourceKey string) resource.TestCheckFunc {
 
 const testCreateTargetConfig = `
 resource "kong_upstream" "upstream" {
-	name				= "MyUpstream"
+	name				= "tf-acc-MyUpstream"
 	slots				= 10
 }
 
@@ -329,7 +391,7 @@ resource "kong_target" "fallback_target" {
 `
 const testUpdateTargetConfig = `
 resource "kong_upstream" "upstream" {
-	name				= "MyUpstream"
+	name				= "tf-acc-MyUpstream"
 	slots 			= 10
 }
 
@@ -347,9 +409,71 @@ resource "kong_target" "fallback_target" {
     tags            = ["d"]
 }
 `
+const testUnhealthyTargetConfig = `
+resource "kong_upstream" "upstream" {
+	name				= "tf-acc-MyUpstream"
+	slots				= 10
+	healthchecks {
+		passive {
+			healthy {
+				successes = 1
+			}
+			unhealthy {
+				http_failures = 1
+			}
+		}
+	}
+}
+
+resource "kong_target" "target" {
+	target			= "mytarget:4000"
+	weight			= 100
+	upstream_id	    = "${kong_upstream.upstream.id}"
+    tags            = ["a", "b"]
+	health_override = "unhealthy"
+}
+
+resource "kong_target" "fallback_target" {
+	target			= "myfallbacktarget:4000"
+	weight			= 50
+	upstream_id	    = "${kong_upstream.upstream.id}"
+    tags            = ["c"]
+}
+`
+const testHealthyTargetConfig = `
+resource "kong_upstream" "upstream" {
+	name				= "tf-acc-MyUpstream"
+	slots				= 10
+	healthchecks {
+		passive {
+			healthy {
+				successes = 1
+			}
+			unhealthy {
+				http_failures = 1
+			}
+		}
+	}
+}
+
+resource "kong_target" "target" {
+	target			= "mytarget:4000"
+	weight			= 100
+	upstream_id	    = "${kong_upstream.upstream.id}"
+    tags            = ["a", "b"]
+	health_override = "healthy"
+}
+
+resource "kong_target" "fallback_target" {
+	target			= "myfallbacktarget:4000"
+	weight			= 50
+	upstream_id	    = "${kong_upstream.upstream.id}"
+    tags            = ["c"]
+}
+`
 const testDeleteTargetConfig = `
 resource "kong_upstream" "upstream" {
-	name				= "MyUpstream"
+	name				= "tf-acc-MyUpstream"
 	slots				= 10
 }
 `
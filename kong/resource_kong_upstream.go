@@ -6,18 +6,28 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kong/go-kong/kong"
 )
 
+var upstreamHashInputs = []string{"none", "consumer", "ip", "header", "cookie", "path", "query_arg", "uri_capture"}
+
 func resourceKongUpstream() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceKongUpstreamCreate,
 		ReadContext:   resourceKongUpstreamRead,
 		DeleteContext: resourceKongUpstreamDelete,
 		UpdateContext: resourceKongUpstreamUpdate,
+		CustomizeDiff: versionedAttributesCustomizeDiff(func() map[string]*schema.Schema { return resourceKongUpstream().Schema }, upstreamVersionedAttributes),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -31,17 +41,26 @@ func resourceKongUpstream() *schema.Resource {
 				ForceNew: false,
 				Default:  10000,
 			},
+			"algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"round-robin", "consistent-hashing", "least-connections"}, false),
+			},
 			"hash_on": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: false,
-				Default:  "none",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice(upstreamHashInputs, false),
 			},
 			"hash_fallback": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: false,
-				Default:  "none",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice(upstreamHashInputs, false),
 			},
 			"hash_on_header": {
 				Type:     schema.TypeString,
@@ -83,6 +102,26 @@ func resourceKongUpstream() *schema.Resource {
 				ForceNew: false,
 				Default:  "/",
 			},
+			"hash_on_query_arg": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"hash_fallback_query_arg": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"hash_on_uri_capture": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"hash_fallback_uri_capture": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
 			"healthchecks": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -90,6 +129,12 @@ func resourceKongUpstream() *schema.Resource {
 				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"threshold": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      0.0,
+							ValidateFunc: validation.FloatBetween(0, 100),
+						},
 						"active": {
 							Type:     schema.TypeList,
 							Optional: true,
@@ -127,6 +172,23 @@ func resourceKongUpstream() *schema.Resource {
 										Optional: true,
 										Default:  nil,
 									},
+									"headers": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"name": {
+													Type:     schema.TypeString,
+													Required: true,
+												},
+												"values": {
+													Type:     schema.TypeList,
+													Required: true,
+													Elem:     &schema.Schema{Type: schema.TypeString},
+												},
+											},
+										},
+									},
 									"healthy": {
 										Type:     schema.TypeList,
 										Optional: true,
@@ -268,15 +330,27 @@ func resourceKongUpstream() *schema.Resource {
 	}
 }
 
+// upstreamVersionedAttributes are the upstream attributes that only newer versions of kong accept.
+var upstreamVersionedAttributes = []versionedAttribute{
+	{name: "hash_on_query_arg", minimumVersion: "3.0.0"},
+	{name: "hash_fallback_query_arg", minimumVersion: "3.0.0"},
+	{name: "hash_on_uri_capture", minimumVersion: "3.0.0"},
+	{name: "hash_fallback_uri_capture", minimumVersion: "3.0.0"},
+}
+
 func resourceKongUpstreamCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	if versionDiags, _ := meta.(*config).checkVersionedAttributes(d, resourceKongUpstream().Schema, upstreamVersionedAttributes); versionDiags.HasError() {
+		return versionDiags
+	}
 
 	upstreamRequest := createKongUpstreamRequestFromResourceData(d)
 
-	client := meta.(*config).adminClient.Upstreams
-	upstream, err := client.Create(ctx, upstreamRequest)
+	upstream, err := createKongUpstream(ctx, meta.(*config).adminClient, upstreamRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create kong upstream: %v error: %v", upstreamRequest, err))
+		return kongErrors.diagnostics("failed to create kong upstream", resourceKongUpstream().Schema, fmt.Errorf("failed to create kong upstream: %s error: %w", redactedRequest(upstreamRequest), err))
 	}
 
 	d.SetId(*upstream.ID)
@@ -285,15 +359,19 @@ func resourceKongUpstreamCreate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceKongUpstreamUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx, kongErrors := recordKongErrors(ctx)
 	d.Partial(false)
 
+	if versionDiags, _ := meta.(*config).checkVersionedAttributes(d, resourceKongUpstream().Schema, upstreamVersionedAttributes); versionDiags.HasError() {
+		return versionDiags
+	}
+
 	upstreamRequest := createKongUpstreamRequestFromResourceData(d)
 
-	client := meta.(*config).adminClient.Upstreams
-	_, err := client.Update(ctx, upstreamRequest)
+	_, err := updateKongUpstream(ctx, meta.(*config).adminClient, upstreamRequest)
 
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error updating kong upstream: %s", err))
+		return kongErrors.diagnostics("error updating kong upstream", resourceKongUpstream().Schema, fmt.Errorf("error updating kong upstream: %w", err))
 	}
 
 	return resourceKongUpstreamRead(ctx, d, meta)
@@ -301,8 +379,7 @@ func resourceKongUpstreamUpdate(ctx context.Context, d *schema.ResourceData, met
 
 func resourceKongUpstreamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*config).adminClient.Upstreams
-	upstream, err := client.Get(ctx, kong.String(d.Id()))
+	upstream, err := getKongUpstream(ctx, meta.(*config).adminClient, kong.String(d.Id()))
 
 	if !kong.IsNotFoundErr(err) && err != nil {
 		return diag.FromErr(fmt.Errorf("could not find kong upstream: %v", err))
@@ -320,6 +397,10 @@ func resourceKongUpstreamRead(ctx context.Context, d *schema.ResourceData, meta
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		err = d.Set("algorithm", upstream.Algorithm)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 		err = d.Set("hash_on", upstream.HashOn)
 		if err != nil {
 			return diag.FromErr(err)
@@ -344,7 +425,23 @@ func resourceKongUpstreamRead(ctx context.Context, d *schema.ResourceData, meta
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		if err := d.Set("healthchecks", flattenHealthCheck(upstream.Healthchecks)); err != nil {
+		err = d.Set("hash_on_query_arg", upstream.HashOnQueryArg)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("hash_fallback_query_arg", upstream.HashFallbackQueryArg)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("hash_on_uri_capture", upstream.HashOnURICapture)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("hash_fallback_uri_capture", upstream.HashFallbackURICapture)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("healthchecks", flattenUpstreamHealthCheck(upstream.Healthchecks)); err != nil {
 			return diag.FromErr(err)
 		}
 		err = d.Set("tags", upstream.Tags)
@@ -380,14 +477,15 @@ func resourceKongUpstreamDelete(ctx context.Context, d *schema.ResourceData, met
 	return diags
 }
 
-func createKongUpstreamRequestFromResourceData(d *schema.ResourceData) *kong.Upstream {
+func createKongUpstreamRequestFromResourceData(d *schema.ResourceData) *kongUpstream {
 
-	upstreamRequest := &kong.Upstream{}
+	upstreamRequest := &kongUpstream{Upstream: &kong.Upstream{}}
 
 	if d.Id() != "" {
 		upstreamRequest.ID = kong.String(d.Id())
 	}
 	upstreamRequest.Name = readStringPtrFromResource(d, "name")
+	upstreamRequest.Algorithm = readStringPtrFromResource(d, "algorithm")
 	upstreamRequest.Slots = readIntPtrFromResource(d, "slots")
 	upstreamRequest.HashOn = readStringPtrFromResource(d, "hash_on")
 	upstreamRequest.HashFallback = readStringPtrFromResource(d, "hash_fallback")
@@ -395,9 +493,23 @@ func createKongUpstreamRequestFromResourceData(d *schema.ResourceData) *kong.Ups
 	upstreamRequest.HashFallbackHeader = readStringPtrFromResource(d, "hash_fallback_header")
 	upstreamRequest.HashOnCookie = readStringPtrFromResource(d, "hash_on_cookie")
 	upstreamRequest.HashOnCookiePath = readStringPtrFromResource(d, "hash_on_cookie_path")
+	upstreamRequest.HashOnQueryArg = readStringPtrFromResource(d, "hash_on_query_arg")
+	upstreamRequest.HashFallbackQueryArg = readStringPtrFromResource(d, "hash_fallback_query_arg")
+	upstreamRequest.HashOnURICapture = readStringPtrFromResource(d, "hash_on_uri_capture")
+	upstreamRequest.HashFallbackURICapture = readStringPtrFromResource(d, "hash_fallback_uri_capture")
 	upstreamRequest.HostHeader = readStringPtrFromResource(d, "host_header")
 	upstreamRequest.Tags = readStringArrayPtrFromResource(d, "tags")
 
+	// the hashing arguments only exist on kong 3.0 and later, they are only cleared when a configuration removes them
+	// so older nodes never see them.
+	if d.Id() != "" {
+		for _, field := range []string{"hash_on_query_arg", "hash_fallback_query_arg", "hash_on_uri_capture", "hash_fallback_uri_capture"} {
+			if d.HasChange(field) && d.Get(field).(string) == "" {
+				upstreamRequest.clearedFields = append(upstreamRequest.clearedFields, field)
+			}
+		}
+	}
+
 	clientCertificateID := readIdPtrFromResource(d, "client_certificate_id")
 	if clientCertificateID != nil {
 		upstreamRequest.ClientCertificate = &kong.Certificate{
@@ -407,7 +519,8 @@ func createKongUpstreamRequestFromResourceData(d *schema.ResourceData) *kong.Ups
 
 	if healthChecksArray := readArrayFromResource(d, "healthchecks"); healthChecksArray != nil && len(healthChecksArray) > 0 {
 		healthChecksMap := healthChecksArray[0].(map[string]interface{})
-		upstreamRequest.Healthchecks = createKongHealthCheckFromMap(&healthChecksMap)
+		upstreamRequest.Healthchecks = newUpstreamHealthcheck(createKongHealthCheckFromMap(&healthChecksMap),
+			readActiveHealthCheckHeadersFromMap(&healthChecksMap))
 	}
 
 	return upstreamRequest
@@ -418,6 +531,10 @@ func createKongHealthCheckFromMap(data *map[string]interface{}) *kong.Healthchec
 		dataMap := *data
 		healthCheck := &kong.Healthcheck{}
 
+		if dataMap["threshold"] != nil {
+			healthCheck.Threshold = kong.Float64(dataMap["threshold"].(float64))
+		}
+
 		if dataMap["active"] != nil {
 			if activeArray := dataMap["active"].([]interface{}); activeArray != nil && len(activeArray) > 0 {
 				activeMap := activeArray[0].(map[string]interface{})
@@ -437,6 +554,35 @@ func createKongHealthCheckFromMap(data *map[string]interface{}) *kong.Healthchec
 	return nil
 }
 
+func readActiveHealthCheckHeadersFromMap(data *map[string]interface{}) map[string][]string {
+	if data == nil || (*data)["active"] == nil {
+		return nil
+	}
+	activeArray := (*data)["active"].([]interface{})
+	if len(activeArray) == 0 || activeArray[0] == nil {
+		return nil
+	}
+	activeMap := activeArray[0].(map[string]interface{})
+	if activeMap["headers"] == nil {
+		return nil
+	}
+
+	headers := map[string][]string{}
+	for _, item := range activeMap["headers"].(*schema.Set).List() {
+		m := item.(map[string]interface{})
+		var values []string
+		for _, v := range m["values"].([]interface{}) {
+			values = append(values, v.(string))
+		}
+		headers[m["name"].(string)] = values
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	return headers
+}
+
 func createKongHealthCheckActiveFromMap(data *map[string]interface{}) *kong.ActiveHealthcheck {
 	if data != nil {
 		dataMap := *data
@@ -605,6 +751,9 @@ func flattenHealthCheck(in *kong.Healthcheck) []interface{} {
 
 	m := make(map[string]interface{})
 
+	if in.Threshold != nil {
+		m["threshold"] = *in.Threshold
+	}
 	if in.Active != nil {
 		m["active"] = flattenHealthCheckActive(in.Active)
 	}
@@ -615,6 +764,32 @@ func flattenHealthCheck(in *kong.Healthcheck) []interface{} {
 	return []interface{}{m}
 }
 
+func flattenUpstreamHealthCheck(in *upstreamHealthcheck) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	healthChecks := flattenHealthCheck(in.toKong())
+	if in.Active != nil && len(in.Active.Headers) > 0 {
+		active := healthChecks[0].(map[string]interface{})["active"].([]interface{})[0].(map[string]interface{})
+		active["headers"] = flattenHealthCheckHeaders(in.Active.Headers)
+	}
+
+	return healthChecks
+}
+
+func flattenHealthCheckHeaders(in map[string][]string) []interface{} {
+	out := make([]interface{}, 0, len(in))
+	for name, values := range in {
+		out = append(out, map[string]interface{}{
+			"name":   name,
+			"values": values,
+		})
+	}
+
+	return out
+}
+
 func flattenHealthCheckActive(in *kong.ActiveHealthcheck) []interface{} {
 	if in == nil {
 		return []interface{}{}
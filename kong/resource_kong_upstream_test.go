@@ -2,12 +2,15 @@ package kong
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
 	"github.com/kong/go-kong/kong"
 )
 
@@ -21,7 +24,7 @@ func TestAccKongUpstream(t *testing.T) {
 				Config: testCreateUpstreamConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongUpstreamExists("kong_upstream.upstream"),
-					resource.TestCheckResourceAttr("kong_upstream.upstream", "name", "MyUpstream"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "name", "tf-acc-MyUpstream"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "slots", "10"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_on", "none"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_fallback", "none"),
@@ -85,7 +88,7 @@ func TestAccKongUpstream(t *testing.T) {
 				Config: fmt.Sprintf(testUpdateUpstreamConfig, testCert1, testKey1),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckKongUpstreamExists("kong_upstream.upstream"),
-					resource.TestCheckResourceAttr("kong_upstream.upstream", "name", "MyUpstream"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "name", "tf-acc-MyUpstream"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "slots", "20"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_on", "header"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_fallback", "cookie"),
@@ -121,6 +124,8 @@ func TestAccKongUpstream(t *testing.T) {
 						return nil
 					},
 
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "healthchecks.0.threshold", "25"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "healthchecks.0.active.0.headers.#", "1"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "healthchecks.0.active.0.type", "https"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "healthchecks.0.active.0.timeout", "10"),
 					resource.TestCheckResourceAttr("kong_upstream.upstream", "healthchecks.0.active.0.concurrency", "20"),
@@ -154,6 +159,37 @@ func TestAccKongUpstream(t *testing.T) {
 	})
 }
 
+func TestAccKongUpstreamAlgorithm(t *testing.T) {
+	skipUnlessKongVersion(t, "3.0.0", "")
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKongUpstreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testCreateUpstreamAlgorithmConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongUpstreamExists("kong_upstream.upstream"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "algorithm", "consistent-hashing"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_on", "query_arg"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_on_query_arg", "tenant"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_fallback", "uri_capture"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_fallback_uri_capture", "id"),
+				),
+			},
+			{
+				Config: testUpdateUpstreamAlgorithmConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongUpstreamExists("kong_upstream.upstream"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "algorithm", "least-connections"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_on", "none"),
+					resource.TestCheckResourceAttr("kong_upstream.upstream", "hash_on_query_arg", ""),
+				),
+			},
+		},
+	})
+}
+
 func TestAccKongUpstreamImport(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
@@ -290,6 +326,92 @@ func TestCreateKongHealthCheckFromMap(t *testing.T) {
 	}
 }
 
+func TestKongUpstreamRequestJSON(t *testing.T) {
+	upstream := &kongUpstream{
+		Upstream: &kong.Upstream{
+			Name:      kong.String("MyUpstream"),
+			Algorithm: kong.String("least-connections"),
+		},
+		HashOnQueryArg: kong.String("tenant"),
+		Healthchecks: newUpstreamHealthcheck(&kong.Healthcheck{
+			Threshold: kong.Float64(25),
+			Active:    &kong.ActiveHealthcheck{HTTPPath: kong.String("/status")},
+		}, map[string][]string{"x-probe": {"kong"}}),
+	}
+
+	out, err := json.Marshal(upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"name":"MyUpstream","algorithm":"least-connections","hash_on_query_arg":"tenant",` +
+		`"healthchecks":{"threshold":25,"active":{"http_path":"/status","headers":{"x-probe":["kong"]}}}}`
+	if string(out) != expected {
+		t.Fatalf("Error matching output and expected: %s vs %s", out, expected)
+	}
+
+	var decoded kongUpstream
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	flattened := flattenUpstreamHealthCheck(decoded.Healthchecks)
+	active := flattened[0].(map[string]interface{})["active"].([]interface{})[0].(map[string]interface{})
+	if !reflect.DeepEqual(active["headers"], []interface{}{map[string]interface{}{"name": "x-probe", "values": []string{"kong"}}}) {
+		t.Fatalf("Error matching flattened headers: %#v", active["headers"])
+	}
+	if flattened[0].(map[string]interface{})["threshold"] != float64(25) {
+		t.Fatalf("Error matching flattened threshold: %#v", flattened[0])
+	}
+}
+
+func TestKongUpstreamUpdateClearsRemovedHashArguments(t *testing.T) {
+	server := fakekong.NewServer("3.0.0")
+	defer server.Close()
+	client, err := GetKongClient(Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	created, err := createKongUpstream(ctx, client, &kongUpstream{
+		Upstream:       &kong.Upstream{Name: kong.String("cleared"), HashOn: kong.String("query_arg")},
+		HashOnQueryArg: kong.String("tenant"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upstreamResource := resourceKongUpstream()
+	state := &terraform.InstanceState{
+		ID:         *created.ID,
+		Attributes: map[string]string{"name": "cleared", "hash_on": "query_arg", "hash_on_query_arg": "tenant"},
+	}
+	diff, err := upstreamResource.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "cleared", "hash_on": "none"}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := schema.InternalMap(upstreamResource.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := createKongUpstreamRequestFromResourceData(d)
+	if !reflect.DeepEqual(request.clearedFields, []string{"hash_on_query_arg"}) {
+		t.Fatalf("expected only hash_on_query_arg to be cleared, got %v", request.clearedFields)
+	}
+	if _, err := updateKongUpstream(ctx, client, request); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := getKongUpstream(ctx, client, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.HashOnQueryArg != nil {
+		t.Errorf("expected the patch to clear hash_on_query_arg, kong still has %q", *updated.HashOnQueryArg)
+	}
+}
+
 func TestCreateKongHealthCheckActiveFromMap(t *testing.T) {
 	cases := []struct {
 		in       *map[string]interface{}
@@ -1070,7 +1192,7 @@ func TestFlattenPassiveUnhealthy(t *testing.T) {
 
 const testCreateUpstreamConfig = `
 resource "kong_upstream" "upstream" {
-	name  		= "MyUpstream"
+	name  		= "tf-acc-MyUpstream"
 	slots 		= 10
 }
 `
@@ -1083,10 +1205,11 @@ EOF
 %s
 EOF
    snis			= ["foo.com"]
+   tags			= ["tf-acc-certificate"]
 }
 
 resource "kong_upstream" "upstream" {
-	name  		         = "MyUpstream"
+	name  		         = "tf-acc-MyUpstream"
 	slots 		         = 20
 	hash_on              = "header"
 	hash_fallback        = "cookie"
@@ -1099,6 +1222,7 @@ resource "kong_upstream" "upstream" {
     client_certificate_id = kong_certificate.certificate.id
 
 	healthchecks {
+		threshold = 25
 		active {
 			type                     = "https"
 			http_path                = "/status"
@@ -1106,6 +1230,10 @@ resource "kong_upstream" "upstream" {
 			concurrency              = 20
 			https_verify_certificate = false
 			https_sni                = "some.domain.com"
+			headers {
+				name   = "x-probe"
+				values = ["kong"]
+			}
 			healthy {
 				successes = 1
 				interval  = 5
@@ -1135,3 +1263,19 @@ resource "kong_upstream" "upstream" {
 	}
 }
 `
+const testCreateUpstreamAlgorithmConfig = `
+resource "kong_upstream" "upstream" {
+	name  		              = "tf-acc-MyUpstream"
+	algorithm                 = "consistent-hashing"
+	hash_on                   = "query_arg"
+	hash_on_query_arg         = "tenant"
+	hash_fallback             = "uri_capture"
+	hash_fallback_uri_capture = "id"
+}
+`
+const testUpdateUpstreamAlgorithmConfig = `
+resource "kong_upstream" "upstream" {
+	name  		= "tf-acc-MyUpstream"
+	algorithm   = "least-connections"
+}
+`
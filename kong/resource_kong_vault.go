@@ -0,0 +1,343 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/kong/go-kong/kong"
+)
+
+// vaultConfigFields are the typed config fields of each vault backend, keyed by the vault name.
+var vaultConfigFields = map[string]map[string]schema.ValueType{
+	"env": {
+		"prefix": schema.TypeString,
+	},
+	"aws": {
+		"region":            schema.TypeString,
+		"endpoint_url":      schema.TypeString,
+		"assume_role_arn":   schema.TypeString,
+		"role_session_name": schema.TypeString,
+	},
+	"gcp": {
+		"project_id": schema.TypeString,
+	},
+	"hcv": {
+		"protocol":            schema.TypeString,
+		"host":                schema.TypeString,
+		"port":                schema.TypeInt,
+		"mount":               schema.TypeString,
+		"kv":                  schema.TypeString,
+		"token":               schema.TypeString,
+		"auth_method":         schema.TypeString,
+		"kube_role":           schema.TypeString,
+		"kube_api_token_file": schema.TypeString,
+		"namespace":           schema.TypeString,
+	},
+}
+
+// vaultCacheFields are the config fields shared by every vault backend.
+var vaultCacheFields = []string{"ttl", "neg_ttl", "resurrect_ttl"}
+
+func resourceKongVault() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKongVaultCreate,
+		ReadContext:   resourceKongVaultRead,
+		DeleteContext: resourceKongVaultDelete,
+		UpdateContext: resourceKongVaultUpdate,
+		CustomizeDiff: resourceKongVaultCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"env", "aws", "gcp", "hcv"}, false),
+			},
+			"prefix": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: false,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: false,
+			},
+			"config": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: vaultConfigSchema(),
+				},
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func vaultConfigSchema() map[string]*schema.Schema {
+	config := map[string]*schema.Schema{}
+
+	for _, field := range vaultCacheFields {
+		config[field] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+		}
+	}
+
+	for backend, fields := range vaultConfigFields {
+		backendSchema := map[string]*schema.Schema{}
+		for field, fieldType := range fields {
+			backendSchema[field] = &schema.Schema{
+				Type:      fieldType,
+				Optional:  true,
+				Sensitive: field == "token",
+			}
+		}
+		config[backend] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     &schema.Resource{Schema: backendSchema},
+		}
+	}
+
+	return config
+}
+
+// resourceKongVaultCustomizeDiff rejects a typed config block that does not belong to the vault backend.
+func resourceKongVaultCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	name := d.Get("name").(string)
+
+	for backend := range vaultConfigFields {
+		if backend == name {
+			continue
+		}
+		if list, ok := d.Get(fmt.Sprintf("config.0.%s", backend)).([]interface{}); ok && len(list) > 0 {
+			return fmt.Errorf("config.%s can not be used with a %s vault", backend, name)
+		}
+	}
+
+	return nil
+}
+
+func resourceKongVaultCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_vault", "3.0.0", isEnterpriseVault(d)); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	vaultRequest := createKongVaultRequestFromResourceData(d)
+
+	client := meta.(*config).adminClient
+	vault, err := createKongVault(ctx, client, vaultRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("failed to create kong vault", resourceKongVault().Schema, fmt.Errorf("failed to create kong vault: %s error: %w", IDToString(vaultRequest.Prefix), err))
+	}
+
+	d.SetId(*vault.ID)
+
+	return resourceKongVaultRead(ctx, d, meta)
+}
+
+func resourceKongVaultUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := meta.(*config).checkResourceSupported("kong_vault", "3.0.0", isEnterpriseVault(d)); diags.HasError() {
+		return diags
+	}
+
+	ctx, kongErrors := recordKongErrors(ctx)
+
+	vaultRequest := createKongVaultRequestFromResourceData(d)
+	vaultRequest.ID = kong.String(d.Id())
+
+	// a field removed from the config is sent as null, leaving it out would keep kong's value
+	oldConfig, _ := d.GetChange("config")
+	for field := range vaultConfigValues(d.Get("name").(string), oldConfig.([]interface{})) {
+		if _, ok := vaultRequest.Config[field]; !ok {
+			if vaultRequest.Config == nil {
+				vaultRequest.Config = map[string]interface{}{}
+			}
+			vaultRequest.Config[field] = nil
+		}
+	}
+
+	client := meta.(*config).adminClient
+	_, err := updateKongVault(ctx, client, vaultRequest)
+
+	if err != nil {
+		return kongErrors.diagnostics("error updating kong vault", resourceKongVault().Schema, fmt.Errorf("error updating kong vault: %w", err))
+	}
+
+	return resourceKongVaultRead(ctx, d, meta)
+}
+
+func resourceKongVaultRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*config).adminClient
+	vault, err := getKongVault(ctx, client, d.Id())
+
+	if kong.IsNotFoundErr(err) {
+		d.SetId("")
+	} else if err != nil {
+		return diag.FromErr(fmt.Errorf("could not find kong vault: %v", err))
+	}
+
+	if vault == nil {
+		d.SetId("")
+	} else {
+		err = d.Set("name", vault.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("prefix", vault.Prefix)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("description", vault.Description)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// kong fills in defaults for the fields that are not configured, only the configured ones are tracked unless
+		// the vault is being imported
+		var configured map[string]interface{}
+		if d.Get("prefix").(string) != "" {
+			configured = vaultConfigValues(IDToString(vault.Name), d.Get("config").([]interface{}))
+		}
+		err = d.Set("config", flattenVaultConfig(IDToString(vault.Name), vault.Config, configured))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("tags", vault.Tags)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func resourceKongVaultDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*config).adminClient
+	err := deleteKongVault(ctx, client, d.Id())
+
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("could not delete kong vault: %v", err))
+	}
+
+	return diags
+}
+
+func createKongVaultRequestFromResourceData(d *schema.ResourceData) *kongVault {
+	name := d.Get("name").(string)
+
+	vaultRequest := &kongVault{
+		Name:        kong.String(name),
+		Prefix:      readStringPtrFromResource(d, "prefix"),
+		Description: readStringPtrFromResource(d, "description"),
+		Tags:        readStringArrayPtrFromResource(d, "tags"),
+	}
+
+	if config := vaultConfigValues(name, d.Get("config").([]interface{})); len(config) > 0 {
+		vaultRequest.Config = config
+	}
+
+	return vaultRequest
+}
+
+// isEnterpriseVault reports whether the vault backend is only available in Kong Enterprise, every backend but env is.
+func isEnterpriseVault(d *schema.ResourceData) bool {
+	return d.Get("name").(string) != "env"
+}
+
+// vaultConfigValues returns the fields set in the typed config block of the vault backend as kong's flat config.
+func vaultConfigValues(name string, configs []interface{}) map[string]interface{} {
+	values := map[string]interface{}{}
+	if len(configs) == 0 || configs[0] == nil {
+		return values
+	}
+
+	config := configs[0].(map[string]interface{})
+
+	for _, field := range vaultCacheFields {
+		if value, ok := config[field].(int); ok && value != 0 {
+			values[field] = value
+		}
+	}
+
+	if backends, ok := config[name].([]interface{}); ok && len(backends) > 0 && backends[0] != nil {
+		for field, value := range backends[0].(map[string]interface{}) {
+			if value != "" && value != 0 {
+				values[field] = value
+			}
+		}
+	}
+
+	return values
+}
+
+// flattenVaultConfig maps the config kong returns onto the typed block of the vault backend. Only the configured
+// fields are kept, or every field when configured is nil.
+func flattenVaultConfig(name string, in map[string]interface{}, configured map[string]interface{}) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	tracked := func(field string) bool {
+		if configured == nil {
+			return true
+		}
+		_, ok := configured[field]
+		return ok
+	}
+
+	config := map[string]interface{}{}
+
+	for _, field := range vaultCacheFields {
+		if value, ok := in[field].(float64); ok && tracked(field) {
+			config[field] = int(value)
+		}
+	}
+
+	backend := map[string]interface{}{}
+	for field := range vaultConfigFields[name] {
+		if !tracked(field) {
+			continue
+		}
+		switch value := in[field].(type) {
+		case string:
+			backend[field] = value
+		case float64:
+			backend[field] = int(value)
+		}
+	}
+	if len(backend) > 0 {
+		config[name] = []interface{}{backend}
+	}
+
+	if len(config) == 0 {
+		return []interface{}{}
+	}
+	return []interface{}{config}
+}
@@ -0,0 +1,237 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestAccKongVault(t *testing.T) {
+	skipUnlessKongVersion(t, "3.0.0", "")
+	skipAgainstFake(t, "vaults")
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKongVaultDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testCreateVaultConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongVaultExists("kong_vault.env"),
+					resource.TestCheckResourceAttr("kong_vault.env", "name", "env"),
+					resource.TestCheckResourceAttr("kong_vault.env", "prefix", "tf-acc-my-env"),
+					resource.TestCheckResourceAttr("kong_vault.env", "config.0.env.0.prefix", "KONG_SECRET_"),
+					resource.TestCheckResourceAttr("kong_vault.env", "tags.#", "1"),
+					testAccCheckKongPluginExists("kong_plugin.rate_limit"),
+				),
+			},
+			{
+				Config: testUpdateVaultConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKongVaultExists("kong_vault.env"),
+					resource.TestCheckResourceAttr("kong_vault.env", "description", "environment secrets"),
+					resource.TestCheckResourceAttr("kong_vault.env", "config.0.env.0.prefix", "KONG_OTHER_"),
+					resource.TestCheckResourceAttr("kong_vault.env", "tags.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "kong_vault.env",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestKongVaultUpdateSendsRemovedConfigAsNull(t *testing.T) {
+	var patched map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			if err := json.NewDecoder(r.Body).Decode(&patched); err != nil {
+				t.Error(err)
+			}
+		}
+		_, _ = w.Write([]byte(`{"id":"vault-id","name":"hcv","prefix":"my-hcv","config":{"protocol":"http","host":"vault.internal","port":8200,"mount":"secret"}}`))
+	}))
+	defer server.Close()
+
+	client, err := GetKongClient(Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &config{adminClient: client}
+	vaultResource := Provider().ResourcesMap["kong_vault"]
+
+	state := &terraform.InstanceState{ID: "vault-id", Attributes: map[string]string{
+		"name":                   "hcv",
+		"prefix":                 "my-hcv",
+		"config.#":               "1",
+		"config.0.ttl":           "60",
+		"config.0.hcv.#":         "1",
+		"config.0.hcv.0.host":    "vault.internal",
+		"config.0.hcv.0.mount":   "kv",
+		"config.0.hcv.0.port":    "0",
+		"config.0.neg_ttl":       "0",
+		"config.0.resurrect_ttl": "0",
+	}}
+	raw := map[string]interface{}{
+		"name":   "hcv",
+		"prefix": "my-hcv",
+		"config": []interface{}{map[string]interface{}{
+			"hcv": []interface{}{map[string]interface{}{"host": "vault.internal"}},
+		}},
+	}
+	diff, err := vaultResource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Empty() {
+		t.Fatal("expected removing config fields to show as a change")
+	}
+	d, err := schema.InternalMap(vaultResource.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := vaultResource.UpdateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	sent := patched["config"].(map[string]interface{})
+	for _, field := range []string{"ttl", "mount"} {
+		if value, ok := sent[field]; !ok || value != nil {
+			t.Errorf("expected the removed %s to be sent as null, got %v", field, sent)
+		}
+	}
+	if sent["host"] != "vault.internal" {
+		t.Errorf("expected the configured host to be sent, got %v", sent)
+	}
+	if hcv := d.Get("config.0.hcv.0").(map[string]interface{}); hcv["protocol"] != "" || hcv["port"] != 0 {
+		t.Errorf("expected only the configured fields to be read, got %v", hcv)
+	}
+}
+
+func TestKongVaultRejectsEnterpriseBackends(t *testing.T) {
+	server := fakekong.NewServer("3.4.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	for _, name := range []string{"aws", "gcp", "hcv"} {
+		vaultResource := p.ResourcesMap["kong_vault"]
+		d := schema.TestResourceDataRaw(t, vaultResource.Schema, map[string]interface{}{"name": name, "prefix": "my-" + name})
+		diags := vaultResource.CreateContext(context.Background(), d, p.Meta())
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires Kong Enterprise") {
+			t.Errorf("expected creating a %s vault against kong oss to be rejected, got %v", name, diags)
+		}
+	}
+}
+
+func testAccCheckKongVaultDestroy(state *terraform.State) error {
+
+	client := testAccProvider.Meta().(*config).adminClient
+
+	for _, rs := range getResourcesByType("kong_vault", state) {
+		vault, err := getKongVault(context.Background(), client, rs.Primary.ID)
+
+		if !kong.IsNotFoundErr(err) && err != nil {
+			return fmt.Errorf("error calling get vault by id: %v", err)
+		}
+
+		if vault != nil {
+			return fmt.Errorf("vault %s still exists, %+v", rs.Primary.ID, vault)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckKongVaultExists(resourceKey string) resource.TestCheckFunc {
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceKey]
+
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceKey)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		vault, err := getKongVault(context.Background(), testAccProvider.Meta().(*config).adminClient, rs.Primary.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if vault == nil {
+			return fmt.Errorf("vault with id %v not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+const testCreateVaultConfig = `
+resource "kong_vault" "env" {
+	name   = "env"
+	prefix = "tf-acc-my-env"
+	config {
+		env {
+			prefix = "KONG_SECRET_"
+		}
+	}
+	tags = ["myTag"]
+}
+
+resource "kong_plugin" "rate_limit" {
+	name        = "rate-limiting"
+	config_json = <<EOT
+	{
+		"second": 5,
+		"policy": "redis",
+		"redis_host": "localhost",
+		"redis_password": "{vault://${kong_vault.env.prefix}/redis-password}"
+	}
+EOT
+}
+`
+
+const testUpdateVaultConfig = `
+resource "kong_vault" "env" {
+	name        = "env"
+	prefix      = "tf-acc-my-env"
+	description = "environment secrets"
+	config {
+		env {
+			prefix = "KONG_OTHER_"
+		}
+	}
+	tags = ["myTag", "anotherTag"]
+}
+
+resource "kong_plugin" "rate_limit" {
+	name        = "rate-limiting"
+	config_json = <<EOT
+	{
+		"second": 5,
+		"policy": "redis",
+		"redis_host": "localhost",
+		"redis_password": "{vault://${kong_vault.env.prefix}/redis-password}"
+	}
+EOT
+}
+`
@@ -108,3 +108,11 @@ func readIntWithZeroPtrFromResource(d *schema.ResourceData, key string) *int {
 	}
 	return kong.Int(value.(int))
 }
+
+func readStringArrayFromSet(set *schema.Set) []string {
+	var array []string
+	for _, x := range set.List() {
+		array = append(array, x.(string))
+	}
+	return array
+}
@@ -0,0 +1,63 @@
+package kong
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+// secretHashPrefix marks a secret that is stored in state as its SHA-256 hash when the provider runs with
+// write_only_secrets.
+const secretHashPrefix = "sha256:"
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return secretHashPrefix + hex.EncodeToString(sum[:])
+}
+
+func isHashedSecret(value string) bool {
+	return strings.HasPrefix(value, secretHashPrefix)
+}
+
+// suppressHashedSecretDiff compares the configured secret against the hash held in state.
+func suppressHashedSecretDiff(k, old, new string, d *schema.ResourceData) bool {
+	return isHashedSecret(old) && new != "" && hashSecret(new) == old
+}
+
+// secretStateValue is the value to keep in state for a secret read back from kong, a vault reference is not a secret
+// and is kept as is.
+func secretStateValue(meta interface{}, secret *string) *string {
+	if secret == nil || *secret == "" || isVaultReference(*secret) || !meta.(*config).writeOnlySecrets {
+		return secret
+	}
+	return kong.String(hashSecret(*secret))
+}
+
+// readSecretPtrFromResource reads a secret to send to kong, a hash taken from state is never sent back.
+func readSecretPtrFromResource(d *schema.ResourceData, key string) *string {
+	if value := unhashedSecret(d.Get(key).(string)); value != "" {
+		return kong.String(value)
+	}
+	return nil
+}
+
+// readSecretWithEmptyPtrFromResource reads a secret to send to kong like readSecretPtrFromResource, a secret that is
+// not set is sent as an empty string the way it always was.
+func readSecretWithEmptyPtrFromResource(d *schema.ResourceData, key string) *string {
+	value := d.Get(key).(string)
+	if isHashedSecret(value) {
+		return nil
+	}
+	return kong.String(value)
+}
+
+// unhashedSecret returns the secret, or an empty string when only its hash is known.
+func unhashedSecret(value string) string {
+	if isHashedSecret(value) {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+func TestHashSecret(t *testing.T) {
+	// echo -n secret | sha256sum
+	expected := "sha256:2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+	if out := hashSecret("secret"); out != expected {
+		t.Fatalf("Error matching output and expected: %s vs %s", out, expected)
+	}
+}
+
+func TestSuppressHashedSecretDiff(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		expected bool
+	}{
+		{old: hashSecret("secret"), new: "secret", expected: true},
+		{old: hashSecret("secret"), new: "changed", expected: false},
+		{old: "secret", new: "secret", expected: false},
+		{old: hashSecret(""), new: "", expected: false},
+	}
+
+	for _, c := range cases {
+		if out := suppressHashedSecretDiff("secret", c.old, c.new, nil); out != c.expected {
+			t.Errorf("Error matching output and expected for %s -> %s: %v vs %v", c.old, c.new, out, c.expected)
+		}
+	}
+}
+
+func TestSecretStateValue(t *testing.T) {
+	writeOnly := &config{writeOnlySecrets: true}
+	plain := &config{}
+
+	if out := secretStateValue(plain, kong.String("secret")); *out != "secret" {
+		t.Errorf("expected the plain secret got %s", *out)
+	}
+	if out := secretStateValue(writeOnly, kong.String("secret")); *out != hashSecret("secret") {
+		t.Errorf("expected the hashed secret got %s", *out)
+	}
+	if out := secretStateValue(writeOnly, kong.String("")); *out != "" {
+		t.Errorf("expected an empty secret to stay empty got %s", *out)
+	}
+	if out := secretStateValue(writeOnly, nil); out != nil {
+		t.Errorf("expected nil got %s", *out)
+	}
+}
+
+func TestReadSecretPtrFromResource(t *testing.T) {
+	resourceSchema := map[string]*schema.Schema{"secret": {Type: schema.TypeString, Optional: true}}
+
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"secret": "secret"})
+	if out := readSecretPtrFromResource(d, "secret"); out == nil || *out != "secret" {
+		t.Errorf("expected the secret to be read got %v", out)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"secret": hashSecret("secret")})
+	if out := readSecretPtrFromResource(d, "secret"); out != nil {
+		t.Errorf("expected a hashed secret not to be sent got %s", *out)
+	}
+}
+
+func TestReadSecretWithEmptyPtrFromResource(t *testing.T) {
+	resourceSchema := map[string]*schema.Schema{"secret": {Type: schema.TypeString, Optional: true}}
+
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+	if out := readSecretWithEmptyPtrFromResource(d, "secret"); out == nil || *out != "" {
+		t.Errorf("expected a secret that is not set to be sent empty got %v", out)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"secret": hashSecret("secret")})
+	if out := readSecretWithEmptyPtrFromResource(d, "secret"); out != nil {
+		t.Errorf("expected a hashed secret not to be sent got %s", *out)
+	}
+}
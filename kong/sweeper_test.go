@@ -0,0 +1,367 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/kong/go-kong/kong"
+)
+
+// testAccPrefix starts the names of the objects acceptance tests create, objects whose name or one of whose tags start
+// with it are deleted by the sweepers.
+const testAccPrefix = "tf-acc-"
+
+// sweepReference is a foreign key of an object to an object of another collection, an object referring to a test
+// object is swept with it.
+type sweepReference struct {
+	field      string
+	path       string
+	nameFields []string
+}
+
+// sweptCollection is an admin api collection swept of the objects acceptance tests leave behind.
+type sweptCollection struct {
+	resource     string
+	path         string
+	nameFields   []string
+	references   []sweepReference
+	dependencies []string
+}
+
+var (
+	sweepServiceReference  = sweepReference{field: "service", path: "/services", nameFields: []string{"name"}}
+	sweepRouteReference    = sweepReference{field: "route", path: "/routes", nameFields: []string{"name"}}
+	sweepConsumerReference = sweepReference{field: "consumer", path: "/consumers", nameFields: []string{"username", "custom_id"}}
+)
+
+// sweptCredentials are the credential collections, they are swept after the upstreams and before the consumers.
+var sweptCredentials = []sweptCollection{
+	{resource: "kong_consumer_key_auth", path: "/key-auths"},
+	{resource: "kong_consumer_basic_auth", path: "/basic-auths", nameFields: []string{"username"}},
+	{resource: "kong_consumer_acl", path: "/acls", nameFields: []string{"group"}},
+	{resource: "kong_consumer_jwt_auth", path: "/jwts"},
+	{resource: "kong_consumer_oauth2", path: "/oauth2", nameFields: []string{"name"}},
+}
+
+// sweptCollections are swept in dependency order: plugins, routes, services, targets, upstreams, credentials and
+// consumers. Targets are swept by their own sweeper as they can only be listed per upstream.
+var sweptCollections = append([]sweptCollection{
+	{
+		resource:   "kong_plugin",
+		path:       "/plugins",
+		references: []sweepReference{sweepServiceReference, sweepRouteReference, sweepConsumerReference},
+	},
+	{
+		resource:     "kong_route",
+		path:         "/routes",
+		nameFields:   []string{"name"},
+		references:   []sweepReference{sweepServiceReference},
+		dependencies: []string{"kong_plugin"},
+	},
+	{
+		resource:     "kong_service",
+		path:         "/services",
+		nameFields:   []string{"name"},
+		dependencies: []string{"kong_route"},
+	},
+	{
+		resource:     "kong_upstream",
+		path:         "/upstreams",
+		nameFields:   []string{"name"},
+		dependencies: []string{"kong_target"},
+	},
+	{
+		resource:     "kong_consumer_group",
+		path:         "/consumer_groups",
+		nameFields:   []string{"name"},
+		dependencies: []string{"kong_plugin"},
+	},
+	{
+		resource:     "kong_consumer",
+		path:         "/consumers",
+		nameFields:   []string{"username", "custom_id"},
+		dependencies: append(sweptCredentialResources(), "kong_consumer_group"),
+	},
+	{
+		resource:     "kong_certificate",
+		path:         "/certificates",
+		dependencies: []string{"kong_service", "kong_upstream"},
+	},
+	{
+		resource:   "kong_key",
+		path:       "/keys",
+		nameFields: []string{"name"},
+		references: []sweepReference{{field: "set", path: "/key-sets", nameFields: []string{"name"}}},
+	},
+	{
+		resource:     "kong_key_set",
+		path:         "/key-sets",
+		nameFields:   []string{"name"},
+		dependencies: []string{"kong_key"},
+	},
+	{
+		resource:     "kong_vault",
+		path:         "/vaults",
+		nameFields:   []string{"prefix"},
+		dependencies: []string{"kong_plugin"},
+	},
+}, credentialCollections()...)
+
+// sweptTargetDependencies are the sweepers that run before the targets are swept.
+var sweptTargetDependencies = []string{"kong_service"}
+
+func sweptCredentialResources() []string {
+	var resources []string
+	for _, credential := range sweptCredentials {
+		resources = append(resources, credential.resource)
+	}
+	return resources
+}
+
+func credentialCollections() []sweptCollection {
+	var credentials []sweptCollection
+	for _, credential := range sweptCredentials {
+		credential.references = []sweepReference{sweepConsumerReference}
+		credential.dependencies = []string{"kong_upstream"}
+		credentials = append(credentials, credential)
+	}
+	return credentials
+}
+
+func init() {
+	for _, collection := range sweptCollections {
+		collection := collection
+		resource.AddTestSweepers(collection.resource, &resource.Sweeper{
+			Name:         collection.resource,
+			Dependencies: collection.dependencies,
+			F: func(region string) error {
+				return sweepCollection(collection)
+			},
+		})
+	}
+
+	resource.AddTestSweepers("kong_target", &resource.Sweeper{
+		Name:         "kong_target",
+		Dependencies: sweptTargetDependencies,
+		F: func(region string) error {
+			return sweepTargets()
+		},
+	})
+}
+
+// sweeperClient connects to the kong at KONG_ADMIN_ADDR, the sweepers run against a shared kong rather than the one
+// TestMain starts for the tests.
+func sweeperClient() (*kong.Client, error) {
+	insecureSkipVerify, _ := strconv.ParseBool(GetEnvVarOrDefault("TLS_SKIP_VERIFY", "false"))
+	return GetKongClient(Config{
+		Address:            GetEnvVarOrDefault(EnvKongAdminHostAddress, "http://localhost:8001"),
+		Username:           GetEnvVarOrDefault(EnvKongAdminUsername, ""),
+		Password:           GetEnvVarOrDefault(EnvKongAdminPassword, ""),
+		InsecureSkipVerify: insecureSkipVerify,
+		APIKey:             GetEnvVarOrDefault("KONG_API_KEY", ""),
+		AdminToken:         GetEnvVarOrDefault("KONG_ADMIN_TOKEN", ""),
+		Workspace:          GetEnvVarOrDefault("KONG_WORKSPACE", ""),
+	})
+}
+
+func sweepCollection(collection sweptCollection) error {
+	ctx := context.Background()
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+
+	entities, err := listForSweep(ctx, client, collection.path)
+	if err != nil {
+		return fmt.Errorf("could not list %s to sweep: %v", collection.path, err)
+	}
+
+	// the test objects of the referenced collections, keyed by path, are looked up once per sweep.
+	referenced := map[string]map[string]bool{}
+	for _, reference := range collection.references {
+		if referenced[reference.path], err = testObjectIDs(ctx, client, reference.path, reference.nameFields); err != nil {
+			return fmt.Errorf("could not list %s to sweep %s: %v", reference.path, collection.path, err)
+		}
+	}
+
+	for _, entity := range entities {
+		sweep := isTestObject(entity, collection.nameFields)
+		for _, reference := range collection.references {
+			if parent, ok := entity[reference.field].(map[string]interface{}); ok && referenced[reference.path][fmt.Sprint(parent["id"])] {
+				sweep = true
+			}
+		}
+		if !sweep {
+			continue
+		}
+
+		id := fmt.Sprint(entity["id"])
+		log.Printf("[INFO] Sweeping %s %s", collection.resource, id)
+		if err := doKongRequest(ctx, client, "DELETE", collection.path+"/"+url.PathEscape(id), nil, nil); err != nil && !kong.IsNotFoundErr(err) {
+			return fmt.Errorf("could not sweep %s %s: %v", collection.resource, id, err)
+		}
+	}
+	return nil
+}
+
+func sweepTargets() error {
+	ctx := context.Background()
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+
+	upstreams, err := testObjectIDs(ctx, client, "/upstreams", []string{"name"})
+	if err != nil {
+		return fmt.Errorf("could not list upstreams to sweep their targets: %v", err)
+	}
+
+	for upstreamID := range upstreams {
+		targets, err := client.Targets.ListAll(ctx, kong.String(upstreamID))
+		if err != nil && !kong.IsNotFoundErr(err) {
+			return fmt.Errorf("could not list the targets of upstream %s to sweep: %v", upstreamID, err)
+		}
+		for _, target := range targets {
+			log.Printf("[INFO] Sweeping kong_target %s", *target.ID)
+			if err := client.Targets.Delete(ctx, kong.String(upstreamID), target.ID); err != nil && !kong.IsNotFoundErr(err) {
+				return fmt.Errorf("could not sweep kong_target %s: %v", *target.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// testObjectIDs returns the ids of the test objects of a collection.
+func testObjectIDs(ctx context.Context, client *kong.Client, path string, nameFields []string) (map[string]bool, error) {
+	entities, err := listForSweep(ctx, client, path)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, entity := range entities {
+		if isTestObject(entity, nameFields) {
+			ids[fmt.Sprint(entity["id"])] = true
+		}
+	}
+	return ids, nil
+}
+
+// listForSweep lists every object of a collection. A collection the kong does not have, such as vaults before Kong
+// 3.0, is empty.
+func listForSweep(ctx context.Context, client *kong.Client, path string) ([]map[string]interface{}, error) {
+	var entities []map[string]interface{}
+	opt := &kong.ListOpt{Size: 1000}
+	for {
+		var page struct {
+			Data   []map[string]interface{} `json:"data"`
+			Offset string                   `json:"offset"`
+		}
+		request, err := client.NewRequest("GET", path, opt, nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := client.Do(ctx, request, &page); err != nil {
+			if kong.IsNotFoundErr(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		entities = append(entities, page.Data...)
+		if page.Offset == "" {
+			return entities, nil
+		}
+		opt.Offset = page.Offset
+	}
+}
+
+func isTestObject(entity map[string]interface{}, nameFields []string) bool {
+	for _, field := range nameFields {
+		if name, ok := entity[field].(string); ok && strings.HasPrefix(name, testAccPrefix) {
+			return true
+		}
+	}
+	tags, _ := entity["tags"].([]interface{})
+	for _, tag := range tags {
+		if name, ok := tag.(string); ok && strings.HasPrefix(name, testAccPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIsTestObject(t *testing.T) {
+	tests := []struct {
+		entity     map[string]interface{}
+		nameFields []string
+		test       bool
+	}{
+		{entity: map[string]interface{}{"name": "tf-acc-service"}, nameFields: []string{"name"}, test: true},
+		{entity: map[string]interface{}{"username": "alice", "custom_id": "tf-acc-alice"}, nameFields: []string{"username", "custom_id"}, test: true},
+		{entity: map[string]interface{}{"tags": []interface{}{"a", "tf-acc-certificate"}}, test: true},
+		{entity: map[string]interface{}{"name": "tf-acc-service"}},
+		{entity: map[string]interface{}{"name": "billing", "tags": []interface{}{"a", "b"}}, nameFields: []string{"name"}},
+		{entity: map[string]interface{}{"name": nil, "tags": nil}, nameFields: []string{"name"}},
+	}
+
+	for _, test := range tests {
+		if isTestObject(test.entity, test.nameFields) != test.test {
+			t.Errorf("expected %v with name fields %v to be a test object: %v", test.entity, test.nameFields, test.test)
+		}
+	}
+}
+
+func TestSweepersRunInDependencyOrder(t *testing.T) {
+	dependencies := map[string][]string{"kong_target": sweptTargetDependencies}
+	byPath := map[string]string{}
+	for _, collection := range sweptCollections {
+		dependencies[collection.resource] = collection.dependencies
+		byPath[collection.path] = collection.resource
+	}
+
+	// runsAfter reports whether the sweeper of resource only runs once the sweeper of before is done.
+	var runsAfter func(resource, before string, seen map[string]bool) bool
+	runsAfter = func(resource, before string, seen map[string]bool) bool {
+		if seen[resource] {
+			return false
+		}
+		seen[resource] = true
+		for _, dependency := range dependencies[resource] {
+			if dependency == before || runsAfter(dependency, before, seen) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for resource, resourceDependencies := range dependencies {
+		for _, dependency := range resourceDependencies {
+			if _, ok := dependencies[dependency]; !ok {
+				t.Errorf("expected the %s dependency of %s to have a sweeper", dependency, resource)
+			}
+		}
+		if runsAfter(resource, resource, map[string]bool{}) {
+			t.Errorf("expected the sweepers of %s not to depend on themselves", resource)
+		}
+	}
+
+	// an object has to be swept before the object it refers to, kong refuses to delete an object still referred to.
+	for _, collection := range sweptCollections {
+		for _, reference := range collection.references {
+			referenced := byPath[reference.path]
+			if !runsAfter(referenced, collection.resource, map[string]bool{}) {
+				t.Errorf("expected %s to be swept before the %s it refers to", collection.resource, referenced)
+			}
+		}
+	}
+	if !runsAfter("kong_upstream", "kong_target", map[string]bool{}) || !runsAfter("kong_certificate", "kong_service", map[string]bool{}) {
+		t.Error("expected the targets and services to be swept before the upstreams and certificates they belong to or refer to")
+	}
+}
@@ -0,0 +1,120 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// kongUpstream adds the upstream fields that go-kong does not model yet. The embedded
+// kong.Upstream is shadowed where Kong expects a richer nested object.
+type kongUpstream struct {
+	*kong.Upstream
+	HashOnQueryArg         *string              `json:"hash_on_query_arg,omitempty"`
+	HashFallbackQueryArg   *string              `json:"hash_fallback_query_arg,omitempty"`
+	HashOnURICapture       *string              `json:"hash_on_uri_capture,omitempty"`
+	HashFallbackURICapture *string              `json:"hash_fallback_uri_capture,omitempty"`
+	Healthchecks           *upstreamHealthcheck `json:"healthchecks,omitempty"`
+	// clearedFields are sent as null so a patch resets them, omitting them would keep the value kong has.
+	clearedFields []string
+}
+
+// kongUpstreamFields marshals the fields of a kongUpstream without its MarshalJSON.
+type kongUpstreamFields kongUpstream
+
+// MarshalJSON adds the cleared fields to the json of the upstream as nulls.
+func (u *kongUpstream) MarshalJSON() ([]byte, error) {
+	body, err := json.Marshal((*kongUpstreamFields)(u))
+	if err != nil || len(u.clearedFields) == 0 {
+		return body, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range u.clearedFields {
+		fields[field] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
+}
+
+type upstreamHealthcheck struct {
+	*kong.Healthcheck
+	Active *upstreamActiveHealthcheck `json:"active,omitempty"`
+}
+
+type upstreamActiveHealthcheck struct {
+	*kong.ActiveHealthcheck
+	Headers map[string][]string `json:"headers,omitempty"`
+}
+
+func newUpstreamHealthcheck(in *kong.Healthcheck, headers map[string][]string) *upstreamHealthcheck {
+	if in == nil {
+		return nil
+	}
+	healthcheck := &upstreamHealthcheck{Healthcheck: in}
+	if in.Active != nil {
+		healthcheck.Active = &upstreamActiveHealthcheck{
+			ActiveHealthcheck: in.Active,
+			Headers:           headers,
+		}
+	}
+
+	return healthcheck
+}
+
+// toKong folds the shadowed active health check back into a kong.Healthcheck.
+func (h *upstreamHealthcheck) toKong() *kong.Healthcheck {
+	if h == nil {
+		return nil
+	}
+	healthcheck := &kong.Healthcheck{}
+	if h.Healthcheck != nil {
+		*healthcheck = *h.Healthcheck
+	}
+	healthcheck.Active = nil
+	if h.Active != nil {
+		healthcheck.Active = h.Active.ActiveHealthcheck
+		if healthcheck.Active == nil {
+			healthcheck.Active = &kong.ActiveHealthcheck{}
+		}
+	}
+
+	return healthcheck
+}
+
+func createKongUpstream(ctx context.Context, client *kong.Client, upstream *kongUpstream) (*kongUpstream, error) {
+	return doKongUpstreamRequest(ctx, client, "POST", "/upstreams", upstream)
+}
+
+func updateKongUpstream(ctx context.Context, client *kong.Client, upstream *kongUpstream) (*kongUpstream, error) {
+	if upstream.ID == nil {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	return doKongUpstreamRequest(ctx, client, "PATCH", fmt.Sprintf("/upstreams/%v", *upstream.ID), upstream)
+}
+
+func getKongUpstream(ctx context.Context, client *kong.Client, upstreamNameOrID *string) (*kongUpstream, error) {
+	return doKongUpstreamRequest(ctx, client, "GET", fmt.Sprintf("/upstreams/%v", *upstreamNameOrID), nil)
+}
+
+func doKongUpstreamRequest(ctx context.Context, client *kong.Client, method string, endpoint string, body *kongUpstream) (*kongUpstream, error) {
+	var req interface{}
+	if body != nil {
+		req = body
+	}
+	request, err := client.NewRequest(method, endpoint, nil, req)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := &kongUpstream{Upstream: &kong.Upstream{}}
+	if _, err := client.Do(ctx, request, upstream); err != nil {
+		return nil, err
+	}
+
+	return upstream, nil
+}
@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"fmt"
@@ -9,7 +10,9 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/kong/go-kong/kong"
 	"github.com/pkg/errors"
 )
@@ -18,13 +21,18 @@ var computedPluginProperties = []string{"created_at", "id", "consumer", "service
 
 // Config holds config details to use to talk to the Kong admin API.
 type Config struct {
-	Address            string
-	Username           string
-	Password           string
-	InsecureSkipVerify bool
-	APIKey             string
-	AdminToken         string
-	Workspace          string
+	Address                  string
+	Username                 string
+	Password                 string
+	InsecureSkipVerify       bool
+	APIKey                   string
+	AdminToken               string
+	Workspace                string
+	DBLess                   bool
+	DeclarativeFormatVersion string
+	MaxRequestsPerSecond     int
+	MaxConcurrentRequests    int
+	RequestTimeout           time.Duration
 }
 
 // HeaderRoundTripper injects Headers into requests
@@ -74,7 +82,10 @@ func GetKongClient(opt Config) (*kong.Client, error) {
 	c := &http.Client{}
 	defaultTransport := http.DefaultTransport.(*http.Transport)
 	defaultTransport.TLSClientConfig = &tlsConfig
-	c.Transport = defaultTransport
+	c.Transport = NewTimeoutRoundTripper(defaultTransport, opt.RequestTimeout)
+	// logged below the declarative layer so db_less mode logs the configuration posted to kong, not the writes it
+	// answers itself
+	c.Transport = NewLoggingRoundTripper(c.Transport, opt.Workspace)
 
 	var headers []string
 	if opt.APIKey != "" {
@@ -89,10 +100,12 @@ func GetKongClient(opt Config) (*kong.Client, error) {
 	if len(headers) > 0 {
 		c.Transport = &HeaderRoundTripper{
 			headers: headers,
-			rt:      defaultTransport,
+			rt:      c.Transport,
 		}
 	}
 
+	c.Transport = NewRateLimitRoundTripper(c.Transport, opt.MaxRequestsPerSecond, opt.MaxConcurrentRequests)
+
 	url, err := url.Parse(opt.Address)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse kong address")
@@ -101,10 +114,19 @@ func GetKongClient(opt Config) (*kong.Client, error) {
 		url.Path = path.Join(url.Path, opt.Workspace)
 	}
 
+	if opt.DBLess {
+		c.Transport = NewDeclarativeRoundTripper(c.Transport, url.String(), opt.DeclarativeFormatVersion)
+	}
+	c.Transport = &KongErrorRoundTripper{rt: c.Transport}
+
 	kongClient, err := kong.NewClient(kong.String(url.String()), c)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating client for Kong's Admin API")
 	}
+	if logging.IsDebugOrHigher() {
+		kongClient.SetDebugMode(true)
+		kongClient.SetLogger(redactingLogWriter{})
+	}
 
 	return kongClient, nil
 }
@@ -157,3 +179,15 @@ func IDToString(v *string) string {
 	}
 	return *v
 }
+
+// doKongRequest sends a request for an entity go-kong has no service for, the response is decoded into out when it
+// is not nil.
+func doKongRequest(ctx context.Context, client *kong.Client, method string, endpoint string, body interface{}, out interface{}) error {
+	request, err := client.NewRequest(method, endpoint, nil, body)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do(ctx, request, out)
+	return err
+}
@@ -0,0 +1,110 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// kongVault is a Kong 3.x vault entity, go-kong does not model vaults yet.
+type kongVault struct {
+	CreatedAt   *int                   `json:"created_at,omitempty"`
+	ID          *string                `json:"id,omitempty"`
+	Name        *string                `json:"name,omitempty"`
+	Prefix      *string                `json:"prefix,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Config      map[string]interface{} `json:"config,omitempty"`
+	Tags        []*string              `json:"tags,omitempty"`
+}
+
+func createKongVault(ctx context.Context, client *kong.Client, vault *kongVault) (*kongVault, error) {
+	created := &kongVault{}
+	if err := doKongRequest(ctx, client, "POST", "/vaults", vault, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func updateKongVault(ctx context.Context, client *kong.Client, vault *kongVault) (*kongVault, error) {
+	if vault.ID == nil {
+		return nil, fmt.Errorf("ID cannot be nil for Update operation")
+	}
+	updated := &kongVault{}
+	if err := doKongRequest(ctx, client, "PATCH", vaultEndpoint(*vault.ID), vault, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func getKongVault(ctx context.Context, client *kong.Client, prefixOrID string) (*kongVault, error) {
+	vault := &kongVault{}
+	if err := doKongRequest(ctx, client, "GET", vaultEndpoint(prefixOrID), nil, vault); err != nil {
+		return nil, err
+	}
+	return vault, nil
+}
+
+func deleteKongVault(ctx context.Context, client *kong.Client, prefixOrID string) error {
+	return doKongRequest(ctx, client, "DELETE", vaultEndpoint(prefixOrID), nil, nil)
+}
+
+func vaultEndpoint(prefixOrID string) string {
+	return "/vaults/" + url.PathEscape(prefixOrID)
+}
+
+// isVaultReference reports whether a value is a reference such as {vault://env/my-secret} that kong resolves through
+// a vault rather than a literal value.
+func isVaultReference(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, "{vault://") && strings.HasSuffix(value, "}")
+}
+
+// preserveVaultReferences copies the vault references of the configured plugin config onto the config read back
+// from kong, so a field kong returns resolved or reformatted is not seen as a change.
+func preserveVaultReferences(configured interface{}, upstream interface{}) interface{} {
+	switch value := configured.(type) {
+	case string:
+		if isVaultReference(value) {
+			return value
+		}
+	case map[string]interface{}:
+		if upstreamMap, ok := upstream.(map[string]interface{}); ok {
+			for key, item := range value {
+				if upstreamItem, ok := upstreamMap[key]; ok {
+					upstreamMap[key] = preserveVaultReferences(item, upstreamItem)
+				}
+			}
+		}
+	case []interface{}:
+		if upstreamList, ok := upstream.([]interface{}); ok && len(upstreamList) == len(value) {
+			for i, item := range value {
+				upstreamList[i] = preserveVaultReferences(item, upstreamList[i])
+			}
+		}
+	}
+	return upstream
+}
+
+// hasVaultReference reports whether a plugin config holds a vault reference anywhere.
+func hasVaultReference(value interface{}) bool {
+	switch value := value.(type) {
+	case string:
+		return isVaultReference(value)
+	case map[string]interface{}:
+		for _, item := range value {
+			if hasVaultReference(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if hasVaultReference(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
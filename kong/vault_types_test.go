@@ -0,0 +1,129 @@
+package kong
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsVaultReference(t *testing.T) {
+	tests := map[string]bool{
+		"{vault://env/my-secret}":                         true,
+		"{vault://aws/secret?region=eu-west-1&version=2}": true,
+		" {vault://hcv/path/key} ":                        true,
+		"vault://env/my-secret":                           false,
+		"{vault://env/my-secret":                          false,
+		"-----BEGIN CERTIFICATE-----":                     false,
+		"":                                                false,
+	}
+
+	for value, expected := range tests {
+		if isVaultReference(value) != expected {
+			t.Errorf("expected isVaultReference(%q) to be %v", value, expected)
+		}
+	}
+}
+
+func TestPreserveVaultReferences(t *testing.T) {
+	configured := map[string]interface{}{
+		"redis_password": "{vault://env/redis-password}",
+		"second":         float64(5),
+		"headers":        []interface{}{"{vault://env/header}", "plain"},
+		"nested": map[string]interface{}{
+			"token": "{vault://hcv/token}",
+		},
+	}
+	upstream := map[string]interface{}{
+		"redis_password": "resolved",
+		"second":         float64(10),
+		"headers":        []interface{}{"resolved", "plain"},
+		"nested": map[string]interface{}{
+			"token": "resolved",
+			"extra": true,
+		},
+		"policy": "local",
+	}
+
+	preserveVaultReferences(configured, upstream)
+
+	expected := map[string]interface{}{
+		"redis_password": "{vault://env/redis-password}",
+		"second":         float64(10),
+		"headers":        []interface{}{"{vault://env/header}", "plain"},
+		"nested": map[string]interface{}{
+			"token": "{vault://hcv/token}",
+			"extra": true,
+		},
+		"policy": "local",
+	}
+	if !reflect.DeepEqual(upstream, expected) {
+		t.Errorf("expected %v got %v", expected, upstream)
+	}
+}
+
+func TestNormalizeDataJSONVaultReference(t *testing.T) {
+	normalized := normalizeDataJSON(`{ "password": "{vault://aws/secret?region=eu-west-1&version=2}" }`)
+
+	expected := `{"password":"{vault://aws/secret?region=eu-west-1&version=2}"}`
+	if normalized != expected {
+		t.Errorf("expected %s got %s", expected, normalized)
+	}
+}
+
+func TestNormalizeDataJSONEscapesHTMLWithoutVaultReferences(t *testing.T) {
+	normalized := normalizeDataJSON(`{ "redirect": "https://example.com/?a=1&b=<2>" }`)
+
+	expected := `{"redirect":"https://example.com/?a=1\u0026b=\u003c2\u003e"}`
+	if normalized != expected {
+		t.Errorf("expected %s got %s", expected, normalized)
+	}
+	if upstream := pluginConfigJSONToString(map[string]interface{}{"redirect": "https://example.com/?a=1&b=<2>"}); upstream != expected {
+		t.Errorf("expected %s got %s", expected, upstream)
+	}
+}
+
+func TestFlattenVaultConfig(t *testing.T) {
+	flattened := flattenVaultConfig("hcv", map[string]interface{}{
+		"protocol": "https",
+		"host":     "vault.internal",
+		"port":     float64(8200),
+		"kv":       "v2",
+		"token":    nil,
+		"ttl":      float64(60),
+	}, nil)
+
+	expected := []interface{}{map[string]interface{}{
+		"ttl": 60,
+		"hcv": []interface{}{map[string]interface{}{
+			"protocol": "https",
+			"host":     "vault.internal",
+			"port":     8200,
+			"kv":       "v2",
+		}},
+	}}
+	if !reflect.DeepEqual(flattened, expected) {
+		t.Errorf("expected %v got %v", expected, flattened)
+	}
+}
+
+func TestFlattenVaultConfigKeepsConfiguredFields(t *testing.T) {
+	flattened := flattenVaultConfig("hcv", map[string]interface{}{
+		"protocol": "http",
+		"host":     "vault.internal",
+		"port":     float64(8200),
+		"mount":    "secret",
+		"ttl":      nil,
+	}, map[string]interface{}{"host": "vault.old", "ttl": 60})
+
+	expected := []interface{}{map[string]interface{}{
+		"hcv": []interface{}{map[string]interface{}{
+			"host": "vault.internal",
+		}},
+	}}
+	if !reflect.DeepEqual(flattened, expected) {
+		t.Errorf("expected %v got %v", expected, flattened)
+	}
+
+	if flattened := flattenVaultConfig("env", map[string]interface{}{"prefix": nil}, map[string]interface{}{}); len(flattened) != 0 {
+		t.Errorf("expected no config block without configured fields, got %v", flattened)
+	}
+}
@@ -0,0 +1,131 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kong/go-kong/kong"
+)
+
+// enterpriseVersionPattern matches the four part versions of Kong Enterprise such as 2.8.1.1.
+var enterpriseVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+\.\d+`)
+
+// attributeGetter reads attributes from a *schema.ResourceData when applying, or from a *schema.ResourceDiff when
+// planning.
+type attributeGetter interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+}
+
+// versionedAttribute is a resource attribute kong only accepts from minimumVersion on.
+type versionedAttribute struct {
+	name           string
+	minimumVersion string
+}
+
+// parseKongVersion reads the version and edition of kong from the response of the root of the admin api.
+func parseKongVersion(root map[string]interface{}) (*semver.Version, bool, error) {
+	raw := kong.VersionFromInfo(root)
+	version, err := kong.ParseSemanticVersion(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	// only the release matters for gating, not the package suffix of the image or the enterprise build.
+	version.Pre, version.Build = nil, nil
+
+	enterprise := strings.Contains(raw, "enterprise") || enterpriseVersionPattern.MatchString(raw) || root["edition"] == "enterprise"
+	return &version, enterprise, nil
+}
+
+// checkVersionedAttributes returns an error diagnostic for every attribute configured with a value the kong the
+// provider talks to is too old to accept. The attributes left at their defaults are returned, they have to be left out
+// of the request as older nodes reject fields they do not know. Nothing is gated when the version is unknown.
+func (c *config) checkVersionedAttributes(d attributeGetter, resourceSchema map[string]*schema.Schema, attributes []versionedAttribute) (diag.Diagnostics, map[string]bool) {
+	var diags diag.Diagnostics
+	omitted := map[string]bool{}
+	if c.kongVersion == nil {
+		return diags, omitted
+	}
+
+	for _, attribute := range attributes {
+		minimum := semver.MustParse(attribute.minimumVersion)
+		if c.kongVersion.GE(minimum) {
+			continue
+		}
+		if !isAttributeConfigured(d, resourceSchema[attribute.name], attribute.name) {
+			omitted[attribute.name] = true
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("%s requires Kong %s or later", attribute.name, attribute.minimumVersion),
+			Detail:        fmt.Sprintf("The Kong node the provider is connected to runs version %s, which does not support %s. Remove the attribute or upgrade Kong.", c.kongVersion, attribute.name),
+			AttributePath: cty.GetAttrPath(attribute.name),
+		})
+	}
+	return diags, omitted
+}
+
+// versionedAttributesCustomizeDiff fails the plan of a resource configuring attributes the kong the provider talks to
+// is too old to accept, so it is not left to the apply.
+func versionedAttributesCustomizeDiff(resourceSchema func() map[string]*schema.Schema, attributes []versionedAttribute) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		c, ok := meta.(*config)
+		if !ok {
+			return nil
+		}
+		diags, _ := c.checkVersionedAttributes(d, resourceSchema(), attributes)
+		var messages []string
+		for _, versionDiag := range diags {
+			messages = append(messages, fmt.Sprintf("%s: %s", versionDiag.Summary, versionDiag.Detail))
+		}
+		if len(messages) > 0 {
+			return fmt.Errorf("%s", strings.Join(messages, "\n"))
+		}
+		return nil
+	}
+}
+
+// isAttributeConfigured reports whether an attribute holds something other than its default, or its zero value when
+// it has no default.
+func isAttributeConfigured(d attributeGetter, attributeSchema *schema.Schema, name string) bool {
+	value := d.Get(name)
+	if attributeSchema != nil && attributeSchema.Default != nil {
+		return !reflect.DeepEqual(value, attributeSchema.Default)
+	}
+	_, ok := d.GetOk(name)
+	return ok
+}
+
+// checkResourceSupported returns an error diagnostic when the kong the provider talks to can not manage a resource
+// type, because it is older than minimumVersion or, when enterprise is set, is not Kong Enterprise. Nothing is gated
+// when the version is unknown.
+func (c *config) checkResourceSupported(resourceType string, minimumVersion string, enterprise bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if c.kongVersion == nil {
+		return diags
+	}
+
+	if enterprise && !c.kongEnterprise {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s requires Kong Enterprise", resourceType),
+			Detail:   fmt.Sprintf("The Kong node the provider is connected to runs the open source edition of version %s, which does not support %s.", c.kongVersion, resourceType),
+		})
+	}
+	if minimumVersion != "" && c.kongVersion.LT(semver.MustParse(minimumVersion)) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s requires Kong %s or later", resourceType, minimumVersion),
+			Detail:   fmt.Sprintf("The Kong node the provider is connected to runs version %s, which does not support %s. Upgrade Kong to manage it.", c.kongVersion, resourceType),
+		})
+	}
+	return diags
+}
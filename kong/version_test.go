@@ -0,0 +1,168 @@
+package kong
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kevholditch/terraform-provider-kong/kong/fakekong"
+)
+
+func TestParseKongVersion(t *testing.T) {
+	tests := []struct {
+		root       map[string]interface{}
+		version    string
+		enterprise bool
+	}{
+		{root: map[string]interface{}{"version": "2.5.0"}, version: "2.5.0"},
+		{root: map[string]interface{}{"version": "3.4.2"}, version: "3.4.2"},
+		{root: map[string]interface{}{"version": "2.8.1.1"}, version: "2.8.1", enterprise: true},
+		{root: map[string]interface{}{"version": "2.1.4-enterprise-edition"}, version: "2.1.4", enterprise: true},
+		{root: map[string]interface{}{"version": "3.4.0", "edition": "enterprise"}, version: "3.4.0", enterprise: true},
+	}
+
+	for _, test := range tests {
+		version, enterprise, err := parseKongVersion(test.root)
+		if err != nil {
+			t.Fatalf("could not parse %v: %v", test.root["version"], err)
+		}
+		if version.String() != test.version || enterprise != test.enterprise {
+			t.Errorf("expected %v to parse as %s enterprise %v, got %s enterprise %v", test.root["version"], test.version, test.enterprise, version, enterprise)
+		}
+	}
+
+	if _, _, err := parseKongVersion(map[string]interface{}{}); err == nil {
+		t.Error("expected a root without a version to fail")
+	}
+}
+
+func TestCheckVersionedAttributes(t *testing.T) {
+	routeSchema := resourceKongRoute().Schema
+	old := semver.MustParse("1.5.0")
+
+	d := schema.TestResourceDataRaw(t, routeSchema, map[string]interface{}{"path_handling": "v1"})
+	diags, omitted := (&config{kongVersion: &old}).checkVersionedAttributes(d, routeSchema, routeVersionedAttributes)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("path_handling")) {
+		t.Fatalf("expected one error for path_handling, got %v", diags)
+	}
+	if !omitted["request_buffering"] || !omitted["response_buffering"] || omitted["path_handling"] {
+		t.Errorf("expected the buffering attributes left at their defaults to be omitted, got %v", omitted)
+	}
+
+	d = schema.TestResourceDataRaw(t, routeSchema, map[string]interface{}{"request_buffering": false})
+	diags, _ = (&config{kongVersion: &old}).checkVersionedAttributes(d, routeSchema, routeVersionedAttributes)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("request_buffering")) {
+		t.Errorf("expected disabling request buffering to be an error, got %v", diags)
+	}
+
+	current := semver.MustParse("3.4.0")
+	diags, omitted = (&config{kongVersion: &current}).checkVersionedAttributes(d, routeSchema, routeVersionedAttributes)
+	if len(diags) != 0 || len(omitted) != 0 {
+		t.Errorf("expected nothing to be gated on a current kong, got %v %v", diags, omitted)
+	}
+
+	diags, omitted = (&config{}).checkVersionedAttributes(d, routeSchema, routeVersionedAttributes)
+	if len(diags) != 0 || len(omitted) != 0 {
+		t.Errorf("expected nothing to be gated without a version, got %v %v", diags, omitted)
+	}
+}
+
+func TestVersionedAttributesFailThePlan(t *testing.T) {
+	old := semver.MustParse("2.2.0")
+	meta := &config{kongVersion: &old}
+
+	tests := map[string]struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		"kong_route":    {map[string]interface{}{"protocols": []interface{}{"http"}, "paths": []interface{}{"/"}, "request_buffering": false}, "request_buffering requires Kong 2.3.0 or later"},
+		"kong_upstream": {map[string]interface{}{"name": "upstream", "hash_on": "query_arg", "hash_on_query_arg": "user"}, "hash_on_query_arg requires Kong 3.0.0 or later"},
+	}
+	for name, test := range tests {
+		resource := Provider().ResourcesMap[name]
+		_, err := resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(test.raw), meta)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("expected planning %s against kong 2.2.0 to fail with %q, got %v", name, test.expected, err)
+		}
+	}
+
+	route := Provider().ResourcesMap["kong_route"]
+	if _, err := route.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{"protocols": []interface{}{"http"}, "paths": []interface{}{"/"}}), meta); err != nil {
+		t.Errorf("expected a route leaving the buffering attributes at their defaults to plan, got %v", err)
+	}
+}
+
+func TestProviderConfigureDetectsVersion(t *testing.T) {
+	server := fakekong.NewServer("2.1.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	c := p.Meta().(*config)
+	if c.kongVersion == nil || c.kongVersion.String() != "2.1.0" || c.kongEnterprise {
+		t.Errorf("expected to detect kong 2.1.0, got %v enterprise %v", c.kongVersion, c.kongEnterprise)
+	}
+}
+
+func TestCheckResourceSupported(t *testing.T) {
+	old := semver.MustParse("2.8.0")
+	current := semver.MustParse("3.4.0")
+
+	tests := []struct {
+		config  *config
+		summary string
+	}{
+		{config: &config{kongVersion: &old}, summary: "kong_key requires Kong 3.1.0 or later"},
+		{config: &config{kongVersion: &current}},
+		{config: &config{}},
+	}
+	for _, test := range tests {
+		diags := test.config.checkResourceSupported("kong_key", "3.1.0", false)
+		if test.summary == "" && len(diags) != 0 {
+			t.Errorf("expected kong_key to be supported on %v, got %v", test.config.kongVersion, diags)
+		}
+		if test.summary != "" && (len(diags) != 1 || diags[0].Summary != test.summary) {
+			t.Errorf("expected %q on %v, got %v", test.summary, test.config.kongVersion, diags)
+		}
+	}
+
+	diags := (&config{kongVersion: &current}).checkResourceSupported("kong_consumer_group", "", true)
+	if len(diags) != 1 || diags[0].Summary != "kong_consumer_group requires Kong Enterprise" {
+		t.Errorf("expected consumer groups to need kong enterprise, got %v", diags)
+	}
+	if diags := (&config{kongVersion: &current, kongEnterprise: true}).checkResourceSupported("kong_consumer_group", "", true); len(diags) != 0 {
+		t.Errorf("expected consumer groups to be supported on kong enterprise, got %v", diags)
+	}
+}
+
+func TestResourcesRejectUnsupportedKong(t *testing.T) {
+	server := fakekong.NewServer("2.8.0")
+	defer server.Close()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{"kong_admin_uri": server.URL})); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	tests := map[string]map[string]interface{}{
+		"kong_vault":                 {"name": "env", "prefix": "my-env"},
+		"kong_key_set":               {"name": "keys"},
+		"kong_consumer_group":        {"name": "gold"},
+		"kong_consumer_group_member": {"consumer_group_id": "gold", "consumer_id": "alice"},
+	}
+	for name, raw := range tests {
+		resource := p.ResourcesMap[name]
+		d := schema.TestResourceDataRaw(t, resource.Schema, raw)
+		diags := resource.CreateContext(context.Background(), d, p.Meta())
+		if !diags.HasError() || !strings.HasPrefix(diags[0].Summary, name+" requires Kong") {
+			t.Errorf("expected creating %s against kong 2.8.0 to be rejected, got %v", name, diags)
+		}
+	}
+}
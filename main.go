@@ -1,11 +1,27 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/kevholditch/terraform-provider-kong/kong"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: kong.Provider})
+		ProviderFunc: kong.Provider,
+		// the provider logs are named after the type of the address, their level is set by TF_LOG_PROVIDER_KONG.
+		ProviderAddr: "registry.terraform.io/kevholditch/kong",
+	})
 }